@@ -0,0 +1,143 @@
+package main
+
+import (
+	"strings"
+
+	"go.rumenx.com/chess/engine"
+)
+
+// a11yAction is a control mirrored outside the canvas for assistive
+// technology (see a11y_js.go). Its label and, for toggles and choices, its
+// pressed state are read again whenever they may have changed.
+type a11yAction struct {
+	label   func() string
+	pressed func() bool // nil for plain actions
+	run     func()
+}
+
+var spokenPieces = map[byte]string{'K': "King", 'Q': "Queen", 'R': "Rook", 'B': "Bishop", 'N': "Knight", 'P': "Pawn"}
+
+// spokenSAN turns SAN into words: "Nxe5+" is "Knight takes e5, check".
+func spokenSAN(san string) string {
+	var suffix string
+	switch {
+	case strings.HasSuffix(san, "#"):
+		suffix = ", " + tr("checkmate")
+	case strings.HasSuffix(san, "+"):
+		suffix = ", " + tr("check")
+	}
+	s := strings.TrimRight(san, "+#!?")
+	switch s {
+	case "O-O":
+		return tr("castles kingside") + suffix
+	case "O-O-O":
+		return tr("castles queenside") + suffix
+	}
+	var promo string
+	if i := strings.IndexByte(s, '='); i >= 0 && i+1 < len(s) {
+		promo = ", " + trf("promotes to %s", tr(strings.ToLower(spokenPieces[s[i+1]])))
+		s = s[:i]
+	}
+	var words []string
+	if len(s) > 0 && spokenPieces[s[0]] != "" && s[0] != 'P' {
+		words = append(words, tr(spokenPieces[s[0]]))
+		s = s[1:]
+	}
+	if len(s) > 2 {
+		from := strings.TrimSuffix(s[:len(s)-2], "x")
+		if from != "" {
+			words = append(words, from) // file, rank or square telling pieces apart
+		}
+		if strings.Contains(s, "x") {
+			words = append(words, tr("takes"))
+		}
+		s = s[len(s)-2:]
+	}
+	words = append(words, s)
+	return strings.Join(words, " ") + promo + suffix
+}
+
+// spokenPieceList names every piece of one colour with its squares, e.g.
+// "White: King e1, Rooks a1 h1, Pawns a2 b2".
+func spokenPieceList(g *engine.Game, c engine.Color) string {
+	squares := map[byte][]string{}
+	board := g.Board()
+	for sq := engine.Square(0); sq < 64; sq++ {
+		if p := board.GetPiece(sq); !p.IsEmpty() && p.Color == c {
+			letter := pieceLetter(p.Type)
+			squares[letter] = append(squares[letter], sq.String())
+		}
+	}
+	var parts []string
+	for _, letter := range []byte("KQRBNP") {
+		if sqs := squares[letter]; len(sqs) > 0 {
+			name := spokenPieces[letter]
+			if len(sqs) > 1 {
+				name += "s"
+			}
+			parts = append(parts, tr(name)+" "+strings.Join(sqs, " "))
+		}
+	}
+	if len(parts) == 0 {
+		return trf("%s: no pieces", tr(c.String()))
+	}
+	return tr(c.String()) + ": " + strings.Join(parts, ", ")
+}
+
+// spokenSquare describes one square, e.g. "e4, black knight".
+func spokenSquare(g *engine.Game, sq engine.Square) string {
+	p := g.Board().GetPiece(sq)
+	if p.IsEmpty() {
+		return trf("%s, empty", sq)
+	}
+	return sq.String() + ", " + tr(strings.ToLower(p.Color.String()+" "+spokenPieces[pieceLetter(p.Type)]))
+}
+
+// a11yActions are the panel controls plus queries for the piece locations,
+// offered as real buttons in the browser.
+func (u *uiGame) a11yActions() []a11yAction {
+	var actions []a11yAction
+	for _, c := range u.panelControls() {
+		actions = append(actions, a11yAction{c.label, c.on, c.activate})
+	}
+	fixed := func(label string) func() string { return func() string { return label } }
+	return append(actions,
+		a11yAction{fixed(tr("New game")), nil, func() { u.newGame(false) }},
+		a11yAction{fixed(tr("Where are White's pieces")), nil, func() { announce(spokenPieceList(u.displayGame(), engine.White)) }},
+		a11yAction{fixed(tr("Where are Black's pieces")), nil, func() { announce(spokenPieceList(u.displayGame(), engine.Black)) }},
+		a11yAction{fixed(tr("Whose turn")), nil, func() { announce(trf("%s to move, %s", tr(u.g.ActiveColor().String()), u.g.Status())) }},
+	)
+}
+
+// updateAnnouncements runs the actions queued by the accessibility buttons,
+// brings their labels and states up to date and announces moves, messages
+// and the keyboard cursor square.
+func (u *uiGame) updateAnnouncements() {
+	for len(u.a11yQueue) > 0 {
+		(<-u.a11yQueue)()
+	}
+	updateAccessibility()
+	if ply := u.ply(); ply != u.a11yPly {
+		switch {
+		case ply < u.a11yPly && ply > 0:
+			announce(trf("Move taken back, %s to move", tr(u.g.ActiveColor().String())))
+		case ply > 0 && ply <= len(u.movesSAN):
+			mover := engine.White
+			if u.g.ActiveColor() == engine.White {
+				mover = engine.Black
+			}
+			announce(tr(mover.String()) + ": " + spokenSAN(u.movesSAN[ply-1]))
+		}
+		u.a11yPly = ply
+	}
+	if u.msg != u.a11yMsg {
+		u.a11yMsg = u.msg
+		if u.msg != "" {
+			announce(u.msg)
+		}
+	}
+	if u.cursor != nil && *u.cursor != u.a11yCursor {
+		u.a11yCursor = *u.cursor
+		announce(spokenSquare(u.displayGame(), u.a11yCursor))
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !(js && wasm)
+
+package main
+
+// setupAccessibility is a no-op on desktop, where the window has no
+// accessibility tree to mirror into.
+func setupAccessibility(actions []a11yAction, run chan<- func()) {}
+
+func updateAccessibility() {}
+
+func announce(text string) {}
@@ -0,0 +1,95 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"strconv"
+	"syscall/js"
+)
+
+// maxAnnouncements is how many past announcements stay in the live region.
+const maxAnnouncements = 5
+
+var liveRegion js.Value
+
+// a11yButton is the DOM button of an action with the label and state last
+// written to it, so the DOM is only touched when they change.
+type a11yButton struct {
+	action  a11yAction
+	el      js.Value
+	label   string
+	pressed bool
+}
+
+var a11yButtons []*a11yButton
+
+// setupAccessibility adds an offscreen ARIA live region for announcements and
+// one real button per action. Clicks are queued on run and executed by
+// Update, so the game state is only touched from Ebiten's goroutine.
+func setupAccessibility(actions []a11yAction, run chan<- func()) {
+	doc := js.Global().Get("document")
+	box := doc.Call("createElement", "div")
+	// visually hidden but kept in the accessibility tree
+	box.Set("style", "position:absolute;left:-10000px;top:auto;width:1px;height:1px;overflow:hidden")
+	liveRegion = doc.Call("createElement", "div")
+	liveRegion.Call("setAttribute", "role", "log")
+	liveRegion.Call("setAttribute", "aria-live", "polite")
+	liveRegion.Call("setAttribute", "aria-label", tr("Chess game announcements"))
+	box.Call("appendChild", liveRegion)
+	nav := doc.Call("createElement", "div")
+	nav.Call("setAttribute", "role", "group")
+	nav.Call("setAttribute", "aria-label", tr("Chess controls"))
+	for _, a := range actions {
+		a := a
+		btn := doc.Call("createElement", "button")
+		btn.Set("type", "button")
+		b := &a11yButton{action: a, el: btn, label: a.label()}
+		btn.Set("textContent", b.label)
+		if a.pressed != nil {
+			b.pressed = a.pressed()
+			btn.Call("setAttribute", "aria-pressed", strconv.FormatBool(b.pressed))
+		}
+		a11yButtons = append(a11yButtons, b)
+		btn.Call("addEventListener", "click", js.FuncOf(func(this js.Value, args []js.Value) any {
+			select {
+			case run <- a.run:
+			default: // queue full, drop the click
+			}
+			return nil
+		}))
+		nav.Call("appendChild", btn)
+	}
+	box.Call("appendChild", nav)
+	doc.Get("body").Call("appendChild", box)
+}
+
+// updateAccessibility refreshes the labels and pressed states of the
+// buttons, such as the toggles and the selected difficulty.
+func updateAccessibility() {
+	for _, b := range a11yButtons {
+		if label := b.action.label(); label != b.label {
+			b.label = label
+			b.el.Set("textContent", label)
+		}
+		if b.action.pressed == nil {
+			continue
+		}
+		if pressed := b.action.pressed(); pressed != b.pressed {
+			b.pressed = pressed
+			b.el.Call("setAttribute", "aria-pressed", strconv.FormatBool(pressed))
+		}
+	}
+}
+
+// announce adds text to the live region for screen readers to speak.
+func announce(text string) {
+	if liveRegion.IsUndefined() || text == "" {
+		return
+	}
+	p := js.Global().Get("document").Call("createElement", "p")
+	p.Set("textContent", text)
+	liveRegion.Call("appendChild", p)
+	for liveRegion.Get("childElementCount").Int() > maxAnnouncements {
+		liveRegion.Get("firstElementChild").Call("remove")
+	}
+}
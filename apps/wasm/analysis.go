@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"go.rumenx.com/chess/ai"
+	"go.rumenx.com/chess/engine"
+)
+
+// moveClass grades a played move by how much evaluation it gave away.
+type moveClass int
+
+const (
+	classBest moveClass = iota
+	classGood
+	classInaccuracy
+	classMistake
+	classBlunder
+)
+
+var moveClassNames = [...]string{"Best", "Good", "Inaccuracy", "Mistake", "Blunder"}
+
+func (c moveClass) String() string { return moveClassNames[c] }
+
+// symbol returns the conventional annotation suffix for the class.
+func (c moveClass) symbol() string {
+	switch c {
+	case classInaccuracy:
+		return "?!"
+	case classMistake:
+		return "?"
+	case classBlunder:
+		return "??"
+	}
+	return ""
+}
+
+// classifyLoss maps a centipawn loss (from the mover's point of view) to a class.
+func classifyLoss(loss int) moveClass {
+	switch {
+	case loss <= 10:
+		return classBest
+	case loss < 50:
+		return classGood
+	case loss < 100:
+		return classInaccuracy
+	case loss < 300:
+		return classMistake
+	}
+	return classBlunder
+}
+
+// moveReview is the verdict for one half-move of the game.
+type moveReview struct {
+	class   moveClass
+	loss    int    // centipawns lost compared with the engine move
+	bestSAN string // engine move in SAN, empty when the played move was best
+	after   evalResult
+	mover   int // 0 when White played the move, 1 when Black did
+}
+
+// gameReport holds the post-game analysis of every ply.
+type gameReport struct {
+	reviews  []moveReview
+	accuracy [2]float64 // White, Black
+}
+
+// annotate implements pgnAnnotator using the review of ply i.
+func (r *gameReport) annotate(i int) (string, string) {
+	rv := r.reviews[i]
+	if rv.class < classInaccuracy {
+		return "", ""
+	}
+	comment := "(" + rv.after.label() + ") " + rv.class.String() + "."
+	if rv.bestSAN != "" {
+		comment += " " + rv.bestSAN + " was best."
+	}
+	return rv.class.symbol(), comment
+}
+
+// centipawns converts a score to a bounded centipawn value, mapping mates to large scores.
+func (e evalResult) centipawns() int {
+	const mateCap = 10000
+	switch {
+	case e.mate > 0:
+		return mateCap - 10*e.mate
+	case e.mate < 0:
+		return -mateCap - 10*e.mate
+	case e.cp > mateCap:
+		return mateCap
+	case e.cp < -mateCap:
+		return -mateCap
+	}
+	return e.cp
+}
+
+// winPercent converts centipawns (mover's view) to an expected score in percent.
+func winPercent(cp int) float64 {
+	return 50 + 50*(2/(1+math.Exp(-0.00368208*float64(cp)))-1)
+}
+
+// moveAccuracy scores a single move from the drop in winning chances.
+func moveAccuracy(before, after float64) float64 {
+	acc := 103.1668*math.Exp(-0.04354*(before-after)) - 3.1669
+	return math.Max(0, math.Min(100, acc))
+}
+
+func sameMove(a, b engine.Move) bool {
+	return a.From == b.From && a.To == b.To && (a.Type != engine.Promotion || a.Promotion == b.Promotion)
+}
+
+// updateReport starts the analysis once when the game has ended.
+func (u *uiGame) updateReport() {
+	ply := u.ply()
+	if u.reportPending || u.aiPending || u.training() || ply == 0 || u.reportCheckedPly == ply {
+		return
+	}
+	u.reportCheckedPly = ply
+	if u.report == nil && len(u.allLegalMoves()) == 0 {
+		u.startReport()
+	}
+}
+
+// startReport analyses every position of the game in the background,
+// asking the AI for its best move and comparing it with the move played.
+func (u *uiGame) startReport() {
+	if u.reportPending {
+		return
+	}
+	moves := append([]engine.Move(nil), u.history()...)
+	if len(moves) == 0 {
+		u.flashMsg(tr("No moves to analyse"))
+		return
+	}
+	u.reportPending = true
+	u.reportProgress = 0
+	gen := u.evalGen
+	start := u.startFEN
+	analyser := ai.NewMinimaxAI(ai.DifficultyHard)
+	go func() {
+		defer func() { u.reportPending = false }()
+		report := &gameReport{reviews: make([]moveReview, len(moves))}
+		var accSum [2]float64
+		var accN [2]int
+		for i, played := range moves {
+			u.reportProgress = i
+			pos, err := replayMoves(start, moves[:i])
+			if err != nil {
+				return
+			}
+			mover := 0
+			if pos.ActiveColor() == engine.Black {
+				mover = 1
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			best, bestErr := analyser.GetBestMove(ctx, pos)
+			cancel()
+
+			next, err := replayMoves(start, moves[:i+1])
+			if err != nil {
+				return
+			}
+			after := evaluatePosition(next)
+			rv := moveReview{after: after, mover: mover}
+			bestEval := after
+			if bestErr == nil && !sameMove(best, played) {
+				if pos.MakeMove(best) == nil {
+					bestEval = evaluatePosition(pos)
+					if san := pos.GenerateSAN(); len(san) > 0 {
+						rv.bestSAN = san[len(san)-1]
+					}
+					_, _ = pos.UndoMove()
+				}
+			}
+			sign := 1
+			if mover == 1 {
+				sign = -1
+			}
+			bestCP := sign * bestEval.centipawns()
+			afterCP := sign * after.centipawns()
+			rv.loss = bestCP - afterCP
+			if rv.loss < 0 {
+				rv.loss = 0
+			}
+			rv.class = classifyLoss(rv.loss)
+			if rv.class == classBest {
+				rv.bestSAN = ""
+			}
+			report.reviews[i] = rv
+			accSum[mover] += moveAccuracy(winPercent(bestCP), winPercent(afterCP))
+			accN[mover]++
+			if gen != u.evalGen {
+				return // game changed underneath us
+			}
+		}
+		for side := range accSum {
+			if accN[side] > 0 {
+				report.accuracy[side] = accSum[side] / float64(accN[side])
+			}
+		}
+		u.report = report
+		u.showReport = true
+		u.flashMsg(tr("Analysis complete (R=report X=PGN)"))
+	}()
+}
+
+// toggleReport shows or hides the report, starting the analysis if needed.
+func (u *uiGame) toggleReport() {
+	if u.report == nil {
+		if u.reportPending {
+			u.flashMsg(tr("Analysis running"))
+			return
+		}
+		u.startReport()
+		return
+	}
+	u.showReport = !u.showReport
+}
+
+// clearReport discards the analysis after the game changes.
+func (u *uiGame) clearReport() {
+	u.report = nil
+	u.showReport = false
+	u.reportCheckedPly = -1
+}
+
+// reviewedSAN returns the SAN of ply i with its annotation symbol, if any.
+func (u *uiGame) reviewedSAN(i int) string {
+	san := u.movesSAN[i]
+	if u.report != nil && i < len(u.report.reviews) {
+		san += u.report.reviews[i].class.symbol()
+	}
+	return san
+}
+
+func (u *uiGame) drawReport(screen *ebiten.Image) {
+	if u.reportPending {
+		msg := trf("Analysing move %d/%d...", u.reportProgress+1, u.ply())
+		printText(screen, msg, 8, 8)
+	}
+	if u.report == nil || !u.showReport {
+		return
+	}
+	r := u.report
+	bg := ebiten.NewImage(boardPixels-80, boardPixels-80)
+	bg.Fill(color.RGBA{0x10, 0x10, 0x10, 0xE0})
+	screen.DrawImage(bg, &ebiten.DrawImageOptions{GeoM: translate(40, 40)})
+
+	var counts [2][len(moveClassNames)]int
+	for _, rv := range r.reviews {
+		counts[rv.mover][rv.class]++
+	}
+	// table rows are tab-separated: a label and right-aligned White and Black columns
+	lines := []string{
+		tr("Accuracy") + fmt.Sprintf("\t%.1f%%\t%.1f%%", r.accuracy[0], r.accuracy[1]),
+		"",
+		"\t" + tr("White") + "\t" + tr("Black"),
+	}
+	for c := classBest; c <= classBlunder; c++ {
+		lines = append(lines, fmt.Sprintf("%s %s\t%d\t%d", tr(c.String()), c.symbol(), counts[0][c], counts[1][c]))
+	}
+	lines = append(lines, "", tr("Key moments:"))
+	maxMoments := 14
+	for i, rv := range r.reviews {
+		if rv.class < classMistake || maxMoments == 0 {
+			continue
+		}
+		maxMoments--
+		n, black := plyNumber(u.startFEN, i)
+		num := stringFromInt(n) + "."
+		if black {
+			num += ".."
+		}
+		line := fmt.Sprintf("%s %s (%s)", num, u.sanText(u.reviewedSAN(i)), rv.after.label())
+		if rv.bestSAN != "" {
+			line += "  " + trf("best %s", u.sanText(rv.bestSAN))
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", tr("R=close  X=export annotated PGN"))
+	drawText(screen, tr("Game report"), titleFace, 56, 52, color.White)
+	top := 52 + int(titleTextSize*1.5)
+	columns := [...]int{0, 300, 380} // right edges of the White and Black columns
+	for i, l := range lines {
+		for col, cell := range strings.Split(l, "\t") {
+			x := 56
+			if col > 0 {
+				x += columns[col] - textWidth(cell, panelFace)
+			}
+			printText(screen, cell, x, top+i*(lineHeight+2))
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"go.rumenx.com/chess/engine"
+)
+
+// markColor is an annotation colour, named by its letter in PGN's
+// [%cal] and [%csl] commands.
+type markColor byte
+
+const (
+	markGreen  markColor = 'G'
+	markRed    markColor = 'R'
+	markBlue   markColor = 'B'
+	markYellow markColor = 'Y'
+)
+
+var markColors = map[markColor]color.RGBA{
+	markGreen:  {0x15, 0x78, 0x1B, 0xA0},
+	markRed:    {0x88, 0x20, 0x20, 0xA0},
+	markBlue:   {0x00, 0x30, 0x88, 0xA0},
+	markYellow: {0xE6, 0x8F, 0x00, 0xA0},
+}
+
+// boardMark is an arrow drawn on the board, or a circle when from and to
+// are the same square.
+type boardMark struct {
+	from, to engine.Square
+	color    markColor
+}
+
+// heldMarkColor picks the colour from the modifier keys: green, Shift red,
+// Alt blue, Shift+Alt or Ctrl yellow.
+func heldMarkColor() markColor {
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	alt := ebiten.IsKeyPressed(ebiten.KeyAlt)
+	switch {
+	case shift && alt, ebiten.IsKeyPressed(ebiten.KeyControl):
+		return markYellow
+	case shift:
+		return markRed
+	case alt:
+		return markBlue
+	}
+	return markGreen
+}
+
+// handleAnnotations draws arrows by dragging with the right button and
+// circles by right-clicking a square. Marks belong to the displayed
+// position; drawing the same mark again removes it. While premoves are
+// queued a right-click cancels them instead.
+func (u *uiGame) handleAnnotations() {
+	x, y := ebiten.CursorPosition()
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		if u.cancelPremoves() {
+			return
+		}
+		if sq, ok := u.squareAt(x, y); ok {
+			u.markFrom = &sq
+		}
+		return
+	}
+	if u.markFrom == nil || !inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonRight) {
+		return
+	}
+	from := *u.markFrom
+	u.markFrom = nil
+	if to, ok := u.squareAt(x, y); ok {
+		u.toggleMark(boardMark{from, to, heldMarkColor()})
+	}
+}
+
+// toggleMark adds m to the displayed position, recolours a mark on the
+// same squares or removes it when it is already there in that colour.
+func (u *uiGame) toggleMark(m boardMark) {
+	ply := u.displayPly()
+	if u.marks == nil {
+		u.marks = map[int][]boardMark{}
+	}
+	marks := u.marks[ply]
+	for i, old := range marks {
+		if old.from != m.from || old.to != m.to {
+			continue
+		}
+		if old.color == m.color {
+			u.marks[ply] = append(marks[:i:i], marks[i+1:]...)
+		} else {
+			marks[i].color = m.color
+		}
+		return
+	}
+	u.marks[ply] = append(marks, m)
+}
+
+// trimMarks forgets the marks of positions taken back.
+func (u *uiGame) trimMarks() {
+	n := u.ply()
+	for ply := range u.marks {
+		if ply > n {
+			delete(u.marks, ply)
+		}
+	}
+}
+
+// drawMarks draws the displayed position's circles and arrows, and the
+// arrow being dragged.
+func (u *uiGame) drawMarks(board *ebiten.Image) {
+	for _, m := range u.marks[u.displayPly()] {
+		u.drawMark(board, m)
+	}
+	if u.markFrom != nil {
+		x, y := u.pointer()
+		if to, ok := u.squareAt(x, y); ok {
+			u.drawMark(board, boardMark{*u.markFrom, to, heldMarkColor()})
+		}
+	}
+}
+
+func (u *uiGame) drawMark(board *ebiten.Image, m boardMark) {
+	c := markColors[m.color]
+	if m.from != m.to {
+		u.drawArrow(board, m.from, m.to, c)
+		return
+	}
+	cx, cy := u.squareCenter(m.from)
+	vector.StrokeCircle(board, cx, cy, squareSize/2-4, squareSize/14, c, true)
+}
+
+// pgnMarks returns the [%csl] and [%cal] commands for the marks of the
+// position after ply moves, or "".
+func (u *uiGame) pgnMarks(ply int) string {
+	var circles, arrows []string
+	for _, m := range u.marks[ply] {
+		if m.from == m.to {
+			circles = append(circles, string(m.color)+m.from.String())
+		} else {
+			arrows = append(arrows, string(m.color)+m.from.String()+m.to.String())
+		}
+	}
+	var cmds []string
+	if len(circles) > 0 {
+		cmds = append(cmds, "[%csl "+strings.Join(circles, ",")+"]")
+	}
+	if len(arrows) > 0 {
+		cmds = append(cmds, "[%cal "+strings.Join(arrows, ",")+"]")
+	}
+	return strings.Join(cmds, " ")
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"go.rumenx.com/chess/engine"
+)
+
+var (
+	whiteImage = ebiten.NewImage(3, 3)
+	// whiteSubImage is the 1x1 source texture used when filling vector paths.
+	whiteSubImage = whiteImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+)
+
+func init() {
+	whiteImage.Fill(color.White)
+}
+
+// squareCenter returns the screen position of the centre of sq, honouring board orientation.
+func (u *uiGame) squareCenter(sq engine.Square) (float32, float32) {
+	vrank := sq.Rank()
+	if u.whiteAtBottom {
+		vrank = 7 - sq.Rank()
+	}
+	x := float32(sq.File()*squareSize + squareSize/2)
+	y := float32(vrank*squareSize + squareSize/2)
+	return x, y
+}
+
+// drawArrow draws a straight arrow from the centre of one square to another.
+func (u *uiGame) drawArrow(screen *ebiten.Image, from, to engine.Square, c color.Color) {
+	if from == to {
+		return
+	}
+	x0, y0 := u.squareCenter(from)
+	x1, y1 := u.squareCenter(to)
+	dx, dy := float64(x1-x0), float64(y1-y0)
+	length := math.Hypot(dx, dy)
+	ux, uy := float32(dx/length), float32(dy/length)
+
+	headLen := float32(squareSize) * 0.35
+	headHalf := float32(squareSize) * 0.2
+	shaft := float32(squareSize) * 0.14
+	// shaft stops at the base of the head so translucent colours don't overlap
+	bx, by := x1-ux*headLen, y1-uy*headLen
+	vector.StrokeLine(screen, x0, y0, bx, by, shaft, c, true)
+
+	var path vector.Path
+	path.MoveTo(x1, y1)
+	path.LineTo(bx-uy*headHalf, by+ux*headHalf)
+	path.LineTo(bx+uy*headHalf, by-ux*headHalf)
+	path.Close()
+	fillPath(screen, &path, c)
+}
+
+// fillPath fills a closed vector path with a solid colour.
+func fillPath(screen *ebiten.Image, path *vector.Path, c color.Color) {
+	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	r, g, b, a := c.RGBA()
+	for i := range vs {
+		vs[i].SrcX = 1
+		vs[i].SrcY = 1
+		vs[i].ColorR = float32(r) / 0xffff
+		vs[i].ColorG = float32(g) / 0xffff
+		vs[i].ColorB = float32(b) / 0xffff
+		vs[i].ColorA = float32(a) / 0xffff
+	}
+	op := &ebiten.DrawTrianglesOptions{AntiAlias: true}
+	screen.DrawTriangles(vs, is, whiteSubImage, op)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"go.rumenx.com/chess/engine"
+)
+
+// blindMode hides pieces for visualization training. Moves are still
+// entered by clicking squares or typing them.
+type blindMode int
+
+const (
+	blindOff      blindMode = iota
+	blindDiscs              // every piece is drawn as a plain disc of its colour
+	blindOpponent           // the opponent's pieces are hidden
+	blindFull               // no pieces are drawn
+)
+
+func (b blindMode) String() string {
+	switch b {
+	case blindDiscs:
+		return "Discs"
+	case blindOpponent:
+		return "Opponent hidden"
+	case blindFull:
+		return "Blindfold"
+	}
+	return "Normal"
+}
+
+// cycleBlind selects the next piece display mode.
+func (u *uiGame) cycleBlind() {
+	u.blind = (u.blind + 1) % (blindFull + 1)
+	u.flashMsg(trf("Pieces: %s (hold Z to reveal)", tr(u.blind.String())))
+}
+
+// revealed reports whether pieces are drawn normally this frame; holding Z
+// shows the position in the training modes.
+func (u *uiGame) revealed() bool {
+	return u.blind == blindOff || (!u.typing && ebiten.IsKeyPressed(ebiten.KeyZ))
+}
+
+// drawBlindPiece draws p on its square in the current blind mode. It returns
+// false when the piece should be drawn normally instead.
+func (u *uiGame) drawBlindPiece(screen *ebiten.Image, p engine.Piece, x, y int) bool {
+	if u.revealed() {
+		return false
+	}
+	switch u.blind {
+	case blindFull:
+		return true
+	case blindOpponent:
+		return p.Color != u.playerColor
+	}
+	fill, line := color.RGBA{0xF6, 0xF6, 0xF6, 0xFF}, color.RGBA{0x33, 0x33, 0x33, 0xFF}
+	if p.Color == engine.Black {
+		fill, line = color.RGBA{0x22, 0x22, 0x22, 0xFF}, color.RGBA{0xEE, 0xEE, 0xEE, 0xFF}
+	}
+	cx, cy, r := float32(x+squareSize/2), float32(y+squareSize/2), float32(squareSize)*0.3
+	vector.DrawFilledCircle(screen, cx, cy, r, fill, true)
+	vector.StrokeCircle(screen, cx, cy, r, 2, line, true)
+	return true
+}
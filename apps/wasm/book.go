@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/base64"
+	"log"
+	"os"
+
+	"go.rumenx.com/chess/ai"
+	"go.rumenx.com/chess/engine"
+)
+
+// embeddedOpeningBook is a small Polyglot book (base64) built from the named
+// main lines of the lichess ECO list, at most 12 plies deep. Weights count how
+// many of those lines share a move.
+var embeddedOpeningBook = "AQu1+VIr4E8DHAACAAAAAAErBpYpjtUDA54AAQAAAAABK891yGPdkwZhAAQAAAAAAWI7qe926QIDFAACAAAAAAG/ebXNywbGDt8AAQAAAAACf2uNaaW+HALbAAQAAAAAArvJgrrcd7cJGgAEAAAAAALdaMiJaFqoDGEAAQAAAAADOI/numiY/g0kAAIAAAAAA4RhscJE8XUNLAAJAAAAAAOEYbHCRPF1Da4ABwAAAAADhGGxwkTxdQyiAAMAAAAAA4RhscJE8XUMaQADAAAAAAOEYbHCRPF1DOsAAgAAAAADhGGxwkTxdQyqAAIAAAAAA4RhscJE8XUM4wABAAAAAAOEYbHCRPF1C1wAAQAAAAADhGGxwkTxdQxhAAEAAAAAA5FVMHPc4kEBWgACAAAAAAOwBLRHkyzvAtsAAQAAAAAEJi6Rno1NBgLbAAEAAAAABLbeBX3JvvUCmgADAAAAAAV/6Hx76kfIDz8AAwAAAAAGZJumm4yf+AKaAHMAAAAABmSbppuMn/gDHAASAAAAAAZkm6abjJ/4AZUADwAAAAAGZJumm4yf+ACdAAMAAAAABmSbppuMn/gDFAADAAAAAAZkm6abjJ/4AFIAAgAAAAAGZJumm4yf+ADTAAEAAAAABmSbppuMn/gDngABAAAAAAZkm6abjJ/4AKYAAQAAAAAGZJumm4yf+ANdAAEAAAAAB1a5RGHFD7AHIwAXAAAAAAdWuURhxQ+wAtsAAgAAAAAHVrlEYcUPsAJZAAEAAAAAB1a5RGHFD7AAUgABAAAAAAdWuURhxQ+wAZUAAQAAAAAHVrlEYcUPsAJRAAEAAAAAB6fuEQJDJnQMogANAAAAAAen7hECQyZ0D60ACQAAAAAHp+4RAkMmdAyqAAUAAAAAB6fuEQJDJnQPdAAEAAAAAAffr3TMOIrqAFIAAQAAAAAH3690zDiK6gOWAAEAAAAAB9+vdMw4iuoCUQABAAAAAAhEkxpu9LmgAZUA5wAAAAAIRJMabvS5oANdAEcAAAAACESTGm70uaABWgAcAAAAAAhEkxpu9LmgAtsAHAAAAAAIRJMabvS5oABSABUAAAAACESTGm70uaABYQADAAAAAAhEkxpu9LmgAtMAAgAAAAAIRJMabvS5oANVAAIAAAAACESTGm70uaACmgACAAAAAAhEkxpu9LmgAQwAAQAAAAAIRJMabvS5oAKSAAEAAAAACESTGm70uaACEAABAAAAAAhEkxpu9LmgAOcAAQAAAAAIRJMabvS5oAFTAAEAAAAACESTGm70uaAAzAABAAAAAAhEkxpu9LmgAN4AAQAAAAAIRJMabvS5oAJRAAEAAAAACESTGm70uaAA1QABAAAAAAhEkxpu9LmgAYwAAQAAAAAIuoCej8ymbwxhABcAAAAACLqAno/Mpm8NLAAHAAAAAAi6gJ6PzKZvDSQAAgAAAAAJMBx3wP+wfwMcAAEAAAAACTAcd8D/sH8C0wABAAAAAAl2sfiUvSnoBuQACgAAAAAJdrH4lL0p6AGVAAQAAAAACXax+JS9KegG4wACAAAAAAl3w2OdMVl5CNwABQAAAAAJoiUPTfyPggCmAAQAAAAACukJ57n1kqIAUgAQAAAAAAtVSuAsUwzXA5YAAgAAAAALjJFALYmY4g5qAAYAAAAAC70S/zxBfkwCWQABAAAAAAvh/wWSfxQQAZUACAAAAAAL4f8Fkn8UEANVAAMAAAAAC+H/BZJ/FBABTAADAAAAAAvh/wWSfxQQA10AAgAAAAAL800n3RrSOwahAAIAAAAADBdAThqJnPkI6QADAAAAAAxre5NjW5bGCJsACwAAAAAOAd2IsVLtbgNdAAQAAAAADhdflsfzchgM4wAEAAAAAA4XX5bH83IYD60AAwAAAAAOUlsTEVhzJQzjAAgAAAAADs+2cfVvChgBTgAJAAAAAA7uhGOtRkw1AFIAAQAAAAAPNymD3Y51UAkVAAgAAAAAD9xxwyfRUx0M4wABAAAAABADmE9odz9aDOMAAwAAAAAQRna/itGSLQLbAB4AAAAAEEZ2v4rRki0AUgAGAAAAABBGdr+K0ZItApoAAwAAAAAQRna/itGSLQJZAAEAAAAAEEZ2v4rRki0CUQABAAAAABBGdr+K0ZItA54AAQAAAAAQRna/itGSLQFaAAEAAAAAEEZ2v4rRki0C0wABAAAAABBikfZ4muHmAxQADAAAAAAQYpH2eJrh5gIQAAIAAAAAEGKR9nia4eYDVQACAAAAABBikfZ4muHmAZUAAgAAAAAQy9A2asUIgwLbAAEAAAAAEXRUXXCYAmUCmgABAAAAABF0VF1wmAJlAZUAAQAAAAARdXplFoF+wQBSAAIAAAAAEjOdt6L2XRAAUgADAAAAABIznbei9l0QAxwAAgAAAAASl7Jm0iZFbQ+tAAEAAAAAE2ud/sjoiuUBlQADAAAAABRtSIgHNnHvAKYABAAAAAAUbUiIBzZx7wckAAIAAAAAFMR+6nshjtUC2wARAAAAABVX2/2wn9VkAKYACAAAAAAVV9v9sJ/VZAMUAAIAAAAAFWmHiOQErK8NLAAMAAAAABVph4jkBKyvDa4AAgAAAAAXJ3Kk7ZJllwVkAAEAAAAAF2HfK7nQ/AACmgAHAAAAABdh3yu50PwAAxwABQAAAAAXYd8rudD8AABSAAQAAAAAF2HfK7nQ/AADlgADAAAAABdh3yu50PwAA54AAgAAAAAXYd8rudD8AAPXAAIAAAAAF2HfK7nQ/AAA0wABAAAAABdh3yu50PwAAZUAAQAAAAAXYd8rudD8AACmAAEAAAAAF9jcNhMIvBcPrwABAAAAABfY3DYTCLwXDKIAAQAAAAAYNVj64qPThwOWAAoAAAAAGDVY+uKj04cCmgAGAAAAABg1WPrio9OHAlEABAAAAAAYNVj64qPThwMUAAEAAAAAGDVY+uKj04cC2wABAAAAABg1WPrio9OHAcYAAQAAAAAYNVj64qPThwLTAAEAAAAAGDVY+uKj04cCGAABAAAAABg96W2vQ+dEAZUAHwAAAAAYPeltr0PnRABSABEAAAAAGNEx/zHytQkNrgAFAAAAABjul3ik8gTGD3YABwAAAAAZHD8XQP9pBgwgAAIAAAAAGoM5j8zfdykOcwACAAAAABqcSswbA43PDGkAAQAAAAAa/OuOfvqXcQbqAAIAAAAAG34TJj/AqzgPYgAPAAAAABt+EyY/wKs4D60ACAAAAAAbfhMmP8CrOAqbAAIAAAAAG34TJj/AqzgPdAACAAAAABwW11oipASGAxwAAwAAAAAcliLRU0QtnwiZAAMAAAAAHJYi0VNELZ8IqQACAAAAABy4pfbU9SY/BVsAEQAAAAAc2NSJRqqD/QGVACQAAAAAHQT7tP1L8ugMYQAFAAAAAB4B2hdsEDU1AFIACAAAAAAeAdoXbBA1NQGVAAIAAAAAHrracRooS4ALXAADAAAAAB662nEaKEuADSwAAQAAAAAeutpxGihLgAyiAAEAAAAAH5PmVv8dQPEA5wACAAAAAB+h3QpmLsFdBuMACQAAAAAgibMJu6pNtg+tAAoAAAAAIImzCbuqTbYO4wAJAAAAACCJswm7qk22DKoAAwAAAAAgibMJu6pNtg0kAAEAAAAAIJmZJrlOqQUPdAACAAAAACCqEAsTNEuDAtsAAwAAAAAgqhALEzRLgwVkAAIAAAAAIMLMXmg+9YcAUgAPAAAAACDVTqprNo9xBtIABQAAAAAhLcfPibK6lgFOAAIAAAAAIarcYMkSkfwAUgACAAAAACIrM/BW+OVwAQcABwAAAAAiRbcHkgmC2QzjAAIAAAAAIrTgUvGPqx0BlQA7AAAAACK04FLxj6sdAFIAGwAAAAAitOBS8Y+rHQOWAAsAAAAAI3eV5WhAEpEM4wANAAAAACN3leVoQBKRDSQAAQAAAAAjd5XlaEASkQyiAAEAAAAAI3rMnrjpwo8CkgACAAAAACOsWmcuEcXQAxwAAgAAAAAkTHyKkrvL7AmeAAkAAAAAJEx8ipK7y+wPdgAFAAAAACSwQ+xH181YAhAAAQAAAAAk/K1dkNkj5QlcAAIAAAAAJV0WbpNaZC8AUgAQAAAAACaGgibCMUTJBVsAGAAAAAAmhoImwjFEyQFaAAYAAAAAJoaCJsIxRMkCkgACAAAAACcN3u93ddHpDCgAAQAAAAAnDd7vd3XR6Q6xAAEAAAAAJ7k4JfdtX9kPdgACAAAAACe9u82dCScNBJwAAwAAAAAoinAptlsT7Q0kAAMAAAAAKIpwKbZbE+0NZQABAAAAACiKcCm2WxPtDOMAAQAAAAAo42FQFsl03QKaAAMAAAAAKONhUBbJdN0G4wACAAAAACnD6CNq+1ObAxwAAQAAAAAp0UOy7IDklQLbAAoAAAAAKdFDsuyA5JUBlQAFAAAAACnRQ7LsgOSVAFIAAwAAAAAp0UOy7IDklQFhAAEAAAAAKdFDsuyA5JUCWQABAAAAACnaoHOY1IgAAZUAAQAAAAAqBCmVCspJ6wGVAAMAAAAAKzkUNSzvUtIM4wACAAAAACuF2ibIPf2sDqgABQAAAAArommySOZ7SAIQAAEAAAAAK7ih9o/Xb8oOagAHAAAAACvDARd6zzf3DSwAAwAAAAAsUD3fe5FXEQzrAAMAAAAALGD96hJb208OsQAEAAAAACxg/eoSW9tPDKIAAQAAAAAsYP3qElvbTw6oAAEAAAAALGnYkqrZXOYBlQABAAAAACxp2JKq2VzmAlkAAQAAAAAtOIjaw2GBSg8/AAIAAAAALfLo9HsCKVINJAADAAAAAC3y6PR7AilSDGkAAQAAAAAt8uj0ewIpUgxhAAEAAAAALfVMl160g0YM4wADAAAAAC4dp3V3nCCxDWUAAQAAAAAuLa0Chhqr7w1lAAEAAAAALp7D6vizSAgOsQACAAAAAC649j7GIyfsBuMAAgAAAAAvL4WXjdWw+QzjAAIAAAAALy+Fl43VsPkNZQABAAAAAC9CjYyxiOzLAxwAAgAAAAAvQo2MsYjsywLbAAEAAAAAL6/GJ8WjvLIJGwAJAAAAAC+vxifFo7yyD60AAwAAAAAvzR734j2Ggg90AAUAAAAAMBkTx+tNLpAC2wADAAAAADAZE8frTS6QAtMAAgAAAAAwkWWi0p4CAgCLAAcAAAAAMMvIibafUn4C2wAEAAAAADDhboDG4fMaDSQAAQAAAAAxVeNfnOU5IwdkAAMAAAAAMVXjX5zlOSMC2wABAAAAADF+Rsv0+vyGDOMAGAAAAAAxfkbL9Pr8hgyiAAMAAAAAMX5Gy/T6/IYMKAACAAAAADF+Rsv0+vyGDOsAAQAAAAAxfkbL9Pr8hg+tAAEAAAAAMX5Gy/T6/IYNZQABAAAAADF+Rsv0+vyGDGEAAQAAAAAyW2lkQAddfQzrABUAAAAAMspbQLGtgmkAUgACAAAAADMco4yTd5+5ApIAAgAAAAA0N4q+0SVtMQOWAAUAAAAANNPjuwJ0C78MogAEAAAAADTT47sCdAu/Da4AAgAAAAA00+O7AnQLvw6eAAIAAAAANNPjuwJ0C78NJAABAAAAADTT47sCdAu/DaYAAQAAAAA04Zz7D55tLg+tAAUAAAAANOGc+w+ebS4OagACAAAAADTw8DbaTLUpD60AAgAAAAA1mnPcTgBPSw0sAAQAAAAANo2/4g/2PD0M6wAMAAAAADaNv+IP9jw9DmoAAgAAAAA2l7D3li8ztA0kAAIAAAAAN35dlyo3dL0BTAADAAAAADd+XZcqN3S9AZUAAgAAAAA3fl2XKjd0vQNdAAIAAAAAN9SG8EeWhnMDFAACAAAAADfUhvBHloZzAZUAAgAAAAA31IbwR5aGcwJRAAEAAAAAN9SG8EeWhnMC2wABAAAAADjGrK5IpzBjA10ABQAAAAA4xqyuSKcwYwMcAAMAAAAAOMasrkinMGMDFAABAAAAADjGrK5IpzBjAZUAAQAAAAA4xqyuSKcwYwLTAAEAAAAAOZ2rBfov1Y4IagACAAAAADmmkd9xUO0+Da4ABQAAAAA5ppHfcVDtPg6oAAMAAAAAOqftGSU1A34M4wABAAAAADq1RoijTrRwCVwAAQAAAAA7AV/jaKQ+2gzrAAQAAAAAOwFtGCiWKhMBlQAEAAAAADuHZGpZByQ4D60AAgAAAAA7mkK1C2AqIw9iAAEAAAAAO5pCtQtgKiMPrQABAAAAADunMxbKHOG+Da4AAQAAAAA7wcnlDUW9rwkcAAEAAAAAPOUjDoig52IDlgAEAAAAADzp259C6Y6fAlkAAQAAAAA9Jla5EW9e7g5qAAEAAAAAPgaWWI0ZO5sBlQAHAAAAAD5HyQGT1I8zAxwAAwAAAAA+R8kBk9SPMwJRAAEAAAAAPkfJAZPUjzMCmgABAAAAAD5HyQGT1I8zAZUAAQAAAAA+rwCcAX7i/gFTAAMAAAAAPshaesBh5XoPdAADAAAAAD7IWnrAYeV6CNoAAwAAAAA/nqaxsglv1AGVAAMAAAAAQGABdsZ7r60AUgACAAAAAEBgAXbGe6+tA5YAAQAAAABAkCfTkjrqrgLbAAQAAAAAQJ9AHSlWwlcJHQADAAAAAEDl/gZf+W/HAIkAAwAAAABBEt970wDW5w7fAAoAAAAAQRLfe9MA1ucPYgAHAAAAAEES33vTANbnD60ABAAAAABB3UZL+8PTUg0kAAIAAAAAQd1GS/vD01INrgABAAAAAEIdTstBzanGAxQAAgAAAABC1hprspis5gcjAAIAAAAAQ6Yfw6kBT/kImwATAAAAAEPtge1WIt8ZAEsABQAAAABElUHZB5D/Ow92AAQAAAAARPkXgL+9z1UM4wABAAAAAET+3m4rEj4eD3YAAgAAAABFEeXzeeDqOQzjAAMAAAAARTU2Dupykm8PYgADAAAAAEU1Ng7qcpJvD1kAAgAAAABGO5YYFpH8nAMcAvsAAAAARjuWGBaR/JwC2wIaAAAAAEY7lhgWkfycAZUASQAAAABGO5YYFpH8nAKaACQAAAAARjuWGBaR/JwAUgAcAAAAAEY7lhgWkfycAlkAGAAAAABGO5YYFpH8nANdABcAAAAARjuWGBaR/JwDlgAUAAAAAEY7lhgWkfycAlEADgAAAABGO5YYFpH8nAOeAAoAAAAARjuWGBaR/JwD3wAIAAAAAEY7lhgWkfycA1UABgAAAABGO5YYFpH8nAIYAAYAAAAARjuWGBaR/JwC0wAFAAAAAEY7lhgWkfycAxQABQAAAABGO5YYFpH8nAIQAAUAAAAARjuWGBaR/JwD1wAEAAAAAEY7lhgWkfycAZcABAAAAABGO5YYFpH8nABQAAQAAAAARjuWGBaR/JwCkgACAAAAAEaQcDw2+DVyCuMAAQAAAABGm6HkhYGeyQLbAAEAAAAARuBZmlDAK90PdgAZAAAAAEbgWZpQwCvdDOMAEQAAAABHTxl1uJr1QwVkAAsAAAAAR08Zdbia9UMBWgADAAAAAEf446+PXkUQDOMAAQAAAABH/8iiuvgdSgFaABAAAAAAR//Iorr4HUoD3wADAAAAAEf/yKK6+B1KAtsAAgAAAABItaN8lJRALQ8/AAIAAAAASQKaf2k1mzAM6wAFAAAAAEnZsy3Un5RVDa4ABQAAAABKPqi1NG5q2wFTAAIAAAAASlv54cEqqBEC2wACAAAAAEp/keGJpmRPD1kAAwAAAABKktE/kIJ3MA0sAAQAAAAASpLRP5CCdzAMaQABAAAAAEqS0T+QgncwDa4AAQAAAABLE3ahchfuHQwoABQAAAAASxN2oXIX7h0PrQADAAAAAEuBDX6nwyrZCGgACQAAAABLgQ1+p8Mq2QBSAAIAAAAAS+Z6iWy6QHoJXAADAAAAAE3DtSTE4mvEDuAAAgAAAABN9oLh4K+UbwYhAAEAAAAATmmdGcf6VukC2wACAAAAAE73bKgDJqg8AxwAAQAAAABPg3x9qswweQ+tAAgAAAAAT/fUWFDI2dcAkAABAAAAAFAZewEHzPFJAFIACQAAAABQGXsBB8zxSQBLAAcAAAAAUBl7AQfM8UkHIwAEAAAAAFDLaqMaMblKAtsAAgAAAABQ4AJ7Lkt91ALbAAEAAAAAUQ8ovKx6SogNrgABAAAAAFN1waYer/8ABVsACgAAAABUGN3hr/zsDgPfAAEAAAAAVMMSY+mtO08NLABkAAAAAFTDEmPprTtPDa4ANAAAAABUwxJj6a07TwyiACQAAAAAVMMSY+mtO08M6wAIAAAAAFTDEmPprTtPDSQAAgAAAABUwxJj6a07Tw5qAAIAAAAAVMMSY+mtO08MqgABAAAAAFTDEmPprTtPDGEAAQAAAABUwxJj6a07Tw2mAAEAAAAAVMMSY+mtO08MaQABAAAAAFW7Esu9fnGGDz8ABAAAAABVuxLLvX5xhg3vAAQAAAAAVg+2MrtGgPcA2wAIAAAAAFYPtjK7RoD3ApIACAAAAABWD7Yyu0aA9wGVAAUAAAAAViVIe8SfIzgAUgAFAAAAAFZKXfiaxpa7ByMAAgAAAABWflfRCe6eRAxqAAIAAAAAVp31UxE/uZ0C2wAEAAAAAFa6UpNX+UhiDa4ABQAAAABW9qVz/1LXiwLTAAUAAAAAVzy1+quG1LQBVwADAAAAAFg+U1q9h556A5YAAgAAAABYPlNavYeeegLbAAIAAAAAWD5TWr2HnnoDXQACAAAAAFhBPbCKvdocAZUADwAAAABYQT2wir3aHAMcAAMAAAAAWE+qTL1XbqoBlQACAAAAAFhi5NMiXoQgAxwAAQAAAABZNM2REkZwUgMcAAIAAAAAWkcsMCd/svsNJAACAAAAAFpHLDAnf7L7DKIAAQAAAABaRywwJ3+y+w0sAAEAAAAAWkcsMCd/svsMqgABAAAAAFpXKoHr+M2jD60AAQAAAABajb+6uEePRQzrAAMAAAAAWsX7gGWZnAUPrQAEAAAAAFrvqrSho2oQDOMAAgAAAABa76q0oaNqEA1lAAEAAAAAW91fFCs0dTgC2wABAAAAAFvdXxQrNHU4AZUAAQAAAABb3V8UKzR1OAABAAEAAAAAW91fFCs0dTgCWQABAAAAAFxJ2uSvuWfiAKYACQAAAABcXkjEKgs+YA8/AAcAAAAAXbTzJmLtp6UBUwACAAAAAF3+4bS4E+OtD60AAQAAAABed2+l6xZ6RQBSAAIAAAAAXndvpesWekUDngABAAAAAF53b6XrFnpFA5YAAQAAAABevzb1ZvQhKgGVAAMAAAAAXvKQ1XKUui8AiQABAAAAAF8T/3xoJ5htAxQAAwAAAABfMnqBoNqypQjgAAUAAAAAXzJ6gaDasqUI6wADAAAAAF/yKCUhesvEAtsAAgAAAABf8iglIXrLxAHXAAEAAAAAYBy12mIwX6cBTgAEAAAAAGActdpiMF+nA9cAAQAAAABgHLXaYjBfpwMcAAEAAAAAYGlQn/T9Za4BTgACAAAAAGDi4cdHj2nGDp4AAQAAAABhbtzgoSuMDgKaAAEAAAAAYW7c4KErjA4C2wABAAAAAGFu3OChK4wOAVoAAQAAAABh0MMlvcb5jg0kAAEAAAAAYdDDJb3G+Y4OngABAAAAAGHQwyW9xvmODa4AAQAAAABiGrq5HxThvQjcAAUAAAAAYm7jfn6nBcYOagACAAAAAGLnXYfB5L9+AEsAAQAAAABjBPyqDNfDwAScAAQAAAAAYx51ufwmPP0BlQAMAAAAAGMgKcyovUU2DSwACgAAAABjICnMqL1FNgjaAAkAAAAAZERvhrrUzUIMogABAAAAAGREb4a61M1CDrEAAQAAAABkTUr+AlZK6wGVAEwAAAAAZE1K/gJWSusAUgAKAAAAAGRNSv4CVkrrAtsABAAAAABkTUr+AlZK6wKSAAIAAAAAZE1K/gJWSusDXQACAAAAAGRNSv4CVkrrApoAAgAAAABkTUr+AlZK6wGMAAEAAAAAZE1K/gJWSusBlwABAAAAAGRNSv4CVkrrAhAAAQAAAABkTUr+AlZK6wIYAAEAAAAAZE1K/gJWSusBDAABAAAAAGRNSv4CVkrrAlkAAQAAAABkTUr+AlZK6wOWAAEAAAAAZE1K/gJWSusCUQABAAAAAGRNSv4CVkrrA98AAQAAAABkTUr+AlZK6wBQAAEAAAAAZE1K/gJWSusC0wABAAAAAGRNSv4CVkrrA54AAQAAAABkTUr+AlZK6wDeAAEAAAAAZE1K/gJWSusBWgABAAAAAGRwkkQf8y5CD60AAwAAAABlAY3ULLaQsA9QAAEAAAAAZVAZNDK1+2EPPwACAAAAAGV/aBz4H9qjBuMACAAAAABlf2gc+B/aowbiAAIAAAAAZX9oHPgf2qMBlQABAAAAAGV/aBz4H9qjAlkAAQAAAABlf2gc+B/aowMcAAEAAAAAZX9oHPgf2qMCmgABAAAAAGYTrc76xMg5D3YABQAAAABmtuv+I8JojgCJAAQAAAAAZrbr/iPCaI4CEAABAAAAAGb1dSvOPlbRD60AAgAAAABnCxf7JVqm9AwoAAEAAAAAZwsX+yVapvQM4wABAAAAAGdSEWiw3ti7DmoAAgAAAABngL1FLMgM/AGVAAIAAAAAaL7qixRhG1IGoQAPAAAAAGmc5Z3n5jD/AxwAAgAAAABppMGgLPPengCJAAQAAAAAaaTBoCzz3p4BlQABAAAAAGnnX3XBD+DBDa4AAQAAAABqYSVrOoaU1A0sAAMAAAAAamElazqGlNQM4wABAAAAAGq66ul810OVApoABQAAAABquurpfNdDlQGVAAMAAAAAat0tWfZGJKQM4wAGAAAAAGrdLVn2RiSkDSQABAAAAABq3S1Z9kYkpA3nAAIAAAAAat0tWfZGJKQNZQACAAAAAGrdLVn2RiSkDKIAAQAAAABq3S1Z9kYkpA2mAAEAAAAAat0tWfZGJKQNrgABAAAAAGrdLVn2RiSkDmoAAQAAAABq3S1Z9kYkpA+tAAEAAAAAaxqLaizn6rcBWgACAAAAAGuIyAuGntPdAtsAFQAAAABroqE+OP4gjQzjAAMAAAAAa/cnMka3bwYPYgAMAAAAAGv3JzJGt28GD60ACQAAAABr9ycyRrdvBgyqAAIAAAAAa/cnMka3bwYMYQACAAAAAGv3JzJGt28GDWUAAQAAAABr9ycyRrdvBgzjAAEAAAAAbIa6Nyasd5UN5wABAAAAAG15hAI71XIiAFIABgAAAABufPa+rho6HgBSAAEAAAAAbrtMCdTnXtIBlQACAAAAAG8On4RtAem3A9cAAQAAAABvD6gOomQsuABSAAUAAAAAcLgMHrtN0l0BTAADAAAAAHC4DB67TdJdAKYAAwAAAABwuAweu03SXQFaAAIAAAAAcQVX7BxIhWwM4wABAAAAAHFFIN63dWP3DKIAAgAAAAByfvwK83UaQwCLAAIAAAAAcpHU5AvBon0PrQABAAAAAHKUGnmXfTDbDKoAAgAAAAB1FF51AyhXiwtzAAIAAAAAdeanAWv+chMNLAACAAAAAHXzk4DaZmEnAxQABAAAAAB185OA2mZhJwKaAAIAAAAAdfOTgNpmYScApgACAAAAAHdcPUTQQU3BDSwAAQAAAAB3XD1E0EFNwQ2uAAEAAAAAd4OzIvFmhdMBlQABAAAAAHfGcUzdo7NPCqMAAwAAAAB4YXz8k1pesw8/AAMAAAAAeGj2RaY8esMM4wABAAAAAHjJFaxaEaAnD60AAgAAAAB4zacOF4N9ngFhACgAAAAAeM2nDheDfZ4C2wAmAAAAAHjNpw4Xg32eAVoAHgAAAAB4zacOF4N9ngBSABgAAAAAeM2nDheDfZ4CkgALAAAAAHjNpw4Xg32eAUwAAgAAAAB4zacOF4N9ngVkAAIAAAAAeM2nDheDfZ4CmgACAAAAAHjiG/jjU4M5CVwAAQAAAAB5AGXWH5oKoQ0kAAEAAAAAeVrUp1x16osNZQAJAAAAAHla1KdcdeqLDGkABgAAAAB5WtSnXHXqiwskAAEAAAAAeVrUp1x16osPWQABAAAAAHla1KdcdeqLDKIAAQAAAAB7LPirxcS0EQ+tABAAAAAAey6rQIEwltwC2wABAAAAAHsuq0CBMJbcApoAAQAAAAB7LqtAgTCW3AJRAAEAAAAAey6rQIEwltwDFAABAAAAAHtnh/wWUAwgAtsABwAAAAB7Z4f8FlAMIAKaAAIAAAAAe62MkULXfVULXgABAAAAAHy6LrAFvYQGAFIADwAAAAB8ui6wBb2EBgCmAAQAAAAAfLtmoZo3U9EC2wAJAAAAAHy7ZqGaN1PRApoAAwAAAAB+naXeJMP0zADjAAIAAAAAfrMimz6gJbkPrQATAAAAAH6zIps+oCW5DnMAAQAAAAB+syKbPqAluQ0kAAEAAAAAfrMimz6gJbkNZQABAAAAAH60DlvH50MDAIkAAQAAAAB+zXKvl+42PQBSAAYAAAAAgDTfalmGN/4CmgAEAAAAAIBVeRSeRsrJBtQABQAAAACAmRQDkmXzKA5qAAMAAAAAgLmz4kPSFAUOagABAAAAAIGONSUwXzh4DSQAAQAAAACCPJtQ/RFBlg0kAY8AAAAAgjybUP0RQZYMogBvAAAAAII8m1D9EUGWDSwANQAAAACCPJtQ/RFBlgyqAC0AAAAAgjybUP0RQZYM4wAeAAAAAII8m1D9EUGWDOsAGwAAAACCPJtQ/RFBlg+tABkAAAAAgjybUP0RQZYNrgAWAAAAAII8m1D9EUGWDmoAFQAAAACCPJtQ/RFBlgxpAAcAAAAAgjybUP0RQZYNpgAFAAAAAII8m1D9EUGWDCgAAwAAAACCPJtQ/RFBlg1tAAMAAAAAgjybUP0RQZYN5wACAAAAAII8m1D9EUGWDCAAAgAAAACCPJtQ/RFBlg+vAAIAAAAAgjybUP0RQZYN7wACAAAAAII8m1D9EUGWDmgAAgAAAACCPJtQ/RFBlg1lAAEAAAAAgrlUfODkw0cEiQAFAAAAAIMOubIHWNHeD60BCgAAAACDDrmyB1jR3gzjAKEAAAAAgw65sgdY0d4NZQAbAAAAAIMOubIHWNHeDSwAEwAAAACDDrmyB1jR3g0kABEAAAAAgw65sgdY0d4MogAPAAAAAIMOubIHWNHeDa4ACQAAAACDDrmyB1jR3gzrAAcAAAAAgw65sgdY0d4OagAGAAAAAIMOubIHWNHeDGkAAwAAAACDDrmyB1jR3gxhAAMAAAAAgw65sgdY0d4MqgACAAAAAIMOubIHWNHeDaYAAQAAAACDDrmyB1jR3g5oAAEAAAAAgyXRRuJbuyEPrQAKAAAAAIM++OfCUaxtAIsAAgAAAACDWr+s1WH/Rw+tABIAAAAAg1q/rNVh/0cPWQACAAAAAINav6zVYf9HDa4AAgAAAACDro5OlEiziwzjAAEAAAAAhBYY2wYLNygDXQABAAAAAIRnDD4t+xKnDOMAAQAAAACEedXCIrcVWA0kAAMAAAAAhVpqDVUMtOQLIwAGAAAAAIW9U+DLhhotDnMAAwAAAACF29oDDhJAFw2mAAIAAAAAhpMwabjIYrsC2wAUAAAAAIbrMMKniowGD60AAgAAAACG7kOlXpC/UwMUAAQAAAAAhu5DpV6Qv1MCmgACAAAAAIbuQ6VekL9TAJ0AAgAAAACHLTYSx18G3w90AAgAAAAAhy02EsdfBt8MogACAAAAAIctNhLHXwbfD1kAAgAAAACH/UI6r6IgRQ5qAAEAAAAAiJVBIT7GFfIPrQACAAAAAIjOSxmr6JkLBWQAEAAAAACIzksZq+iZCwLbAAIAAAAAiM5LGavomQsBWgACAAAAAIjOSxmr6JkLAFIAAgAAAACI72hkoJOWVQ0kAAEAAAAAiSldpfwpR1YPdAACAAAAAIpHBILYgzT/DKoAMgAAAACKRwSC2IM0/w0sACMAAAAAikcEgtiDNP8I2gAVAAAAAIpHBILYgzT/DmoAAwAAAACKRwSC2IM0/w+tAAEAAAAAikcEgtiDNP8OpQABAAAAAIpHBILYgzT/DKIAAQAAAACKRwSC2IM0/wxhAAEAAAAAikcEgtiDNP8NJAABAAAAAIrwYeDophU2CVwAAQAAAACLOHkHjYw9AwFOAAcAAAAAi1yys9Yze6sPNQABAAAAAItcsrPWM3urDGkAAQAAAACLa/+cLYajSAzjAAEAAAAAi3nPIBa14YcM4wABAAAAAIt9fYJbJzw+ANgAAwAAAACLmeISdGiwDAFTAAgAAAAAjFNd067kXn4DXQACAAAAAIx0XwihhVpNApIAAgAAAACMh/Lj3M/l2Q+tAAMAAAAAjWtaxNOg4coMogADAAAAAI18htCwSPVtD3QABgAAAACNfIbQsEj1bQtcAAUAAAAAjXyG0LBI9W0MYQACAAAAAI2npz5f3XLcDSQAAwAAAACNp6c+X91y3AwgAAEAAAAAjd6+ZT8fKV8DHAAGAAAAAI7f/j3VmBC7AxQAAgAAAACO7+t1FxzKUQVbAA4AAAAAj27WS9DjhVMNJAABAAAAAI9u1kvQ44VTD3YAAQAAAACRc8K5YebWZwCJAAEAAAAAkZH6eXL1cTwPdgACAAAAAJH2Pcn4ZBYNAFIABAAAAACR9j3J+GQWDQJZAAEAAAAAkf6MXrWEIs4CmgACAAAAAJIo9qzh2xPBBy0AAwAAAACSuUW0Z+p9uwOWAAsAAAAAkrlFtGfqfbsAUgADAAAAAJK5RbRn6n27AhAAAgAAAACS6yIDj+/igAq5AAIAAAAAk6ja+QD2lN8PdgACAAAAAJPhRf0N9KpOAKYACAAAAACT4UX9DfSqTgCdAAMAAAAAk/gYvCT72z0C2wACAAAAAJQIxrjUBeiMAxwAAwAAAACUFsfeoax4gAjbAAMAAAAAlBbH3qGseIAMYQABAAAAAJQWx96hrHiACNoAAQAAAACUkziuOC646gyiAAIAAAAAlJM4rjguuOoPrQABAAAAAJSX3FBHY4PWDOsABQAAAACUmLCsYIauYQzrAAIAAAAAlN3kssRfYu8M4wACAAAAAJTwQTxp/ABGA98AAgAAAACVabpb9KNxfAFOAAIAAAAAlcflE83bJnsOsQABAAAAAJXVLdE/glCWCRsAAQAAAACV3QP+dYP1zwMcAAIAAAAAld0D/nWD9c8BlQACAAAAAJXrTrqciJ6YDmoABwAAAACV6066nIiemA1tAAEAAAAAletOupyInpgM6wABAAAAAJXrTrqciJ6YDt8AAQAAAACV6+4cbPi4xw0kAAEAAAAAlevuHGz4uMcPrQABAAAAAJX+RQkL+JQIDSQAAQAAAACV/kUJC/iUCAxhAAEAAAAAlmJveww3i7cPrQABAAAAAJbunKD5N/D7DSQAAwAAAACXBQWTDCqUcwFTAAIAAAAAmD+9C/3hZAcCkgADAAAAAJi3MW5qX8fvAFIAFwAAAACYtzFual/H7wMUAAUAAAAAmVs38xvHd2INJAABAAAAAJl2/I1A49IFDCgADwAAAACZsPU/euNZ/AFEAAUAAAAAmeSHUpU3FsEIWAASAAAAAJoTjdIfM7wqDnMAAwAAAACaE43SHzO8Kg90AAMAAAAAmhON0h8zvCoMogACAAAAAJo9PYqv0F77AU4ABwAAAACauRGFqAUA6gCJAAQAAAAAmr1HneWtl8sGowAFAAAAAJrHB48ws+aGDKIAAgAAAACa+cRCY8kO1wzjAAIAAAAAm0JAD/rfVwcPrQAFAAAAAJtCQA/631cHDa4AAQAAAACcZembyd45KgzjAAMAAAAAnTPA2fnGzVgNZQACAAAAAJ1Xy3kzl6liDOsAAQAAAACdX3rufnedoQzjABoAAAAAnV967n53naEPrQAUAAAAAJ1feu5+d52hDKIABQAAAACdX3rufnedoQzrAAMAAAAAnV967n53naENZQADAAAAAJ1feu5+d52hDSQAAgAAAACdX3rufnedoQ0sAAIAAAAAnV967n53naEN7wABAAAAAJ1feu5+d52hDCAAAQAAAACdX3rufnedoQxpAAEAAAAAnV967n53naENrgABAAAAAJ1feu5+d52hDCgAAQAAAACdX3rufnedoQ1tAAEAAAAAnV967n53naEOagABAAAAAJ1feu5+d52hDGEAAQAAAACdX3rufnedoQ2mAAEAAAAAnV967n53naEMqgABAAAAAJ1feu5+d52hDmgAAQAAAACdwzFu2RQaCA8/AAQAAAAAng6Lborh55MBUwACAAAAAJ5CHDggsOIkDmoAAQAAAACejnrQvwgmwA0sAAEAAAAAnuhwvjr9WHoImwABAAAAAJ8HIMyYCCfqDu0ABAAAAACfYgUr+jHg4QLbAAEAAAAAn9pSXMC0yDIOagAGAAAAAJ/aUlzAtMgyDSwAAwAAAACgYUmiPBg94QOWAAUAAAAAoUW97g46AwAC2wAEAAAAAKF4ZVQTn2epCJsABAAAAACi8nhjJb7r2wMcAAEAAAAAovJ4YyW+69sBDQABAAAAAKLyeGMlvuvbA54AAQAAAACkG7iSibDirQjcAAEAAAAApKViI2I4MHwAUgAIAAAAAKTA/nRVxt/aDSQAAwAAAACk5eyPrA/UzALbAAEAAAAApOXsj6wP1MwDHAABAAAAAKWpnln/CFcACyMABQAAAAClqZ5Z/whXAAxhAAQAAAAApeB3Byn0+JwPrQACAAAAAKYdt/H0lFy3AxwABwAAAACmHbfx9JRctwCmAAMAAAAApsoB++5e9GENbQACAAAAAKbKAfvuXvRhDKIAAQAAAACmygH77l70YQzrAAEAAAAApuBQzypkAnQM4wABAAAAAKeCeqx5CSDSC3MAAgAAAACniuC+6OfRZAYgAAEAAAAAp4rgvujn0WQAEAABAAAAAKeK4L7o59FkA98AAQAAAACnlN+ihG8Lqw+tAAEAAAAAqD2/TQHseu8BWgADAAAAAKg9v00B7HrvA10AAgAAAACoUqrOX7XPbAMUAAMAAAAAqFKqzl+1z2wA0QACAAAAAKhpFRCATEEZA54AAQAAAACon26DwmDNYAOWAAMAAAAAqOfJEWFBb+sPrQAQAAAAAKkVeoMYmcWDAQcAAgAAAACpZIZfSYzAoQ5qAAEAAAAAqa19vS7l1mUNJAAFAAAAAKnYK6Xhb0JxDusAAgAAAACp2Cul4W9CcQ6lAAEAAAAAqdgrpeFvQnEMqgABAAAAAKnw9o//m1HmAZUAAgAAAACqkCWw1u1R7QNVABAAAAAAq0nZFL/TF1wPrQACAAAAAKuOY6PFLnOQCNwABgAAAACsT3kbyylhpgyiAAIAAAAArOdf1JmA5O4J3wABAAAAAK0tSjGVG8q7AFIABQAAAACteDD3vh4J+Q2uAAEAAAAArbJQ2QZ9oeEM6wAEAAAAAK3gUj0qj13LAxwAAQAAAACuveehl1f+nw92ABAAAAAArr3noZdX/p8PrQACAAAAAK6956GXV/6fDOsAAQAAAACuveehl1f+nw1lAAEAAAAArr3noZdX/p8PrwABAAAAAK7IS6bUMjVuDOMAAQAAAACuyEum1DI1bg3nAAEAAAAArshLptQyNW4NJAABAAAAAK/z5uNyKX7wAQcAAgAAAACv8+bjcil+8ABSAAIAAAAAsDCb8Vz1igMM4wACAAAAALEmEP76g6vVAhgABQAAAACxJhD++oOr1QMcAAIAAAAAsd4GHxQxIqgPdgACAAAAALHeBh8UMSKoD68AAQAAAACylYLdq9cZ6QBSAAYAAAAAsrLDzRbqMDIG4wAiAAAAALLhqVVJWeReD60AAQAAAACy817mzGqeDQjqAAIAAAAAs37yqw2bv7UPYgABAAAAALOW1d3TEpQZA54AAQAAAAC0FGTfaC6yoABSAAsAAAAAtBRk32gusqAHJAAEAAAAALQUZN9oLrKgAZUAAgAAAAC0LN79UaURHQ0kAAEAAAAAtGQoBO1nYDIM6wAGAAAAALTRzKUB2KinBVsABQAAAAC1GBpIxvxbIwyiAAEAAAAAtlFrdgpofzkM4wABAAAAALZjV3318kG7AlkABgAAAAC2Y1d99fJBuwKSAAMAAAAAtuJbKAvHCZUNLAACAAAAALcg10Ed3dEuAIkAAgAAAAC3acNCrwskVQlcAAIAAAAAt+c+84mcgKkBjAACAAAAALfnPvOJnICpAVMAAgAAAAC4RMU6wFskCwMUAA4AAAAAuHqZT5TAXcAOcwALAAAAALiruVhU9SZkC2MAEQAAAAC4q7lYVPUmZAtcAAEAAAAAuKu5WFT1JmQLfgABAAAAALmOSQuL/n6TDOsABwAAAAC6TrPJT6l0LwajAAYAAAAAuplmylUECywCEAABAAAAALq0L9PVIJizApoAAwAAAAC6tC/T1SCYswGVAAIAAAAAurQv09UgmLMDHAABAAAAALst6xUPAMEVDOMABwAAAAC7LesVDwDBFQ0kAAUAAAAAuy3rFQ8AwRUNZQADAAAAALst6xUPAMEVD60AAwAAAAC7LesVDwDBFQ3vAAEAAAAAuy3rFQ8AwRUPrwABAAAAALst6xUPAMEVDGEAAQAAAAC7LesVDwDBFQ2mAAEAAAAAuz+VDLiC0mEPdAAFAAAAALs/lQy4gtJhD1kAAwAAAAC7cuaknRvtHgzjAAEAAAAAu4YNMS9pCPsC2wAWAAAAALuGDTEvaQj7A10AAgAAAAC7hg0xL2kI+wPfAAEAAAAAu4YNMS9pCPsDngABAAAAALv3GdQEmS10D60AAQAAAAC79xnUBJktdAzrAAEAAAAAvG/7DU28x8kC2wADAAAAALxv+w1NvMfJAZUAAgAAAAC8b/sNTbzHyQBSAAEAAAAAvORRs0QubcEOagACAAAAALzlFrAI0z4zAxwAAQAAAAC9T9NEXMVpQg8/AAcAAAAAvU/TRFzFaUIMogACAAAAAL1P00RcxWlCDGkAAgAAAAC9d0E4kiKpBABSACsAAAAAvXdBOJIiqQQBlQAEAAAAAL13QTiSIqkEA5YAAwAAAAC9rI661HN+RQzjAAsAAAAAvayOutRzfkUNJAAHAAAAAL2sjrrUc35FDKIABAAAAAC9rI661HN+RQ+tAAEAAAAAvayOutRzfkUMYQABAAAAAL2sjrrUc35FDWUAAQAAAAC9rI661HN+RQzrAAEAAAAAvayOutRzfkUNrgABAAAAAL3E8Ym2RaGyDOsAAgAAAAC9yuv88InNtw5qAAEAAAAAvcrr/PCJzbcPtAABAAAAAL3K6/zwic23DW0AAQAAAAC9+IikBkpQ3AkbACIAAAAAvfiIpAZKUNwKmwADAAAAAL4bhOqQ+bueCJsAAgAAAAC+UqhWB5khYgtjAAMAAAAAvl/+9Yc9pYMOsQAKAAAAAL7I5F4Ke9/sDCAAAQAAAAC++pOAiYyASA+vAAIAAAAAvxV7jIg2XhkG4QAEAAAAAL8Ve4yINl4ZAFIAAgAAAAC/KaYIarAr1gzrABgAAAAAvymmCGqwK9YOagATAAAAAL8ppghqsCvWDSwAEAAAAAC/KaYIarAr1g2uAAYAAAAAvymmCGqwK9YNZQACAAAAAL8ppghqsCvWDSQAAQAAAAC/KaYIarAr1gwoAAEAAAAAvymmCGqwK9YO4AABAAAAAL8ppghqsCvWDe8AAQAAAAC/KaYIarAr1gxhAAEAAAAAvymmCGqwK9YMaQABAAAAAL8ppghqsCvWDvIAAQAAAAC/KaYIarAr1g+tAAEAAAAAwHvH5+U0pB8GowADAAAAAMCsdPpXJRaRA5YAAwAAAADAua/lJYJOPQ+tAAIAAAAAwRMBO49EYEQOagABAAAAAMEw9bvn14GnAFIAAQAAAADB0wl/AiofbQ+tAAcAAAAAwf1calTI6SkC3AAFAAAAAMIIpDE1qlYADz8AAgAAAADCY5bucAwi8gjcABMAAAAAw1kFm8elhnkPrQAeAAAAAMOKe47MFGX7AZUACAAAAADDinuOzBRl+wCmAAIAAAAAxCHDgXYnvpwHFQALAAAAAMWt9Exkpeo9AKYAAgAAAADF0NGjUTYN6gjbAAMAAAAAxdDRo1E2DeoNJAABAAAAAMXQ0aNRNg3qDaYAAQAAAADGhSseVloBrQ2uAAEAAAAAxrFOG9ON3DcDlgAHAAAAAMaxThvTjdw3ApoABgAAAADGsU4b043cNwMUAAEAAAAAxrFOG9ON3DcAUgABAAAAAMaxThvTjdw3AhgAAQAAAADGsU4b043cNwMcAAEAAAAAxrFOG9ON3DcCWQABAAAAAMaxThvTjdw3AlEAAQAAAADG6mNpjIWFhQKSAAUAAAAAxupjaYyFhYUCWQACAAAAAMbqY2mMhYWFAMwAAgAAAADG+2xRfRcGtgckAAIAAAAAx0pEJ3hsk14N7wABAAAAAMdKRCd4bJNeDSQAAQAAAADHSkQneGyTXg0sAAEAAAAAx0pEJ3hsk14PdgABAAAAAMdMWa0qqjiTCZ8ABAAAAADHvDh/LkziwA2mABcAAAAAx7w4fy5M4sAM4wADAAAAAMe8OH8uTOLADOsAAgAAAADHvDh/LkziwA5qAAIAAAAAyA9xCKAO69gPrQADAAAAAMldQ+JzTwCwD60AAgAAAADJXlQ5cpK7hALbAAwAAAAAyYM7nG82oMUBBwACAAAAAMnGwllMIVJXD60ABAAAAADJxsJZTCFSVw6lAAIAAAAAyhC4qxh+Y1gPrQABAAAAAMoYCTxVnlebDSwABwAAAADKGAk8VZ5Xmw+tAAYAAAAAyhgJPFWeV5sNZQAFAAAAAMoYCTxVnlebDSQABAAAAADKGAk8VZ5Xmw2uAAMAAAAAyhgJPFWeV5sMogADAAAAAMoYCTxVnlebDaYAAgAAAADKGAk8VZ5XmwyqAAIAAAAAyhgJPFWeV5sOagABAAAAAMoYCTxVnlebDOMAAQAAAADKGAk8VZ5XmwxhAAEAAAAAyp32TMwcl/ENJAAEAAAAAMqd9kzMHJfxDOMAAgAAAADKnfZMzByX8Q1lAAIAAAAAyp32TMwcl/EPrQACAAAAAMqd9kzMHJfxDKIAAQAAAADKnfZMzByX8QxhAAEAAAAAyp32TMwcl/EMaQABAAAAAMrpiStGxvE/DmoAAQAAAADLjgtlKa+sSQtcAAIAAAAAy6VOAWgmK2QC0wABAAAAAMulTgFoJitkBBoAAQAAAADMBTPd0TadPQ0kAAEAAAAAzLwwwHvu3SoBlQABAAAAAM1aQyWvUS6QAtsAAgAAAADNcbywfz2U4gkbABkAAAAAzXG8sH89lOIM4wABAAAAAM1xvLB/PZTiDOsAAQAAAADOUVwqR/qwAgtjAAMAAAAAz/hyOf12VEoNLAADAAAAANASwoQK3391AFIABQAAAADQhRPz+pl1LQ8/AAkAAAAA0Oh9tkVv+d8BlQAHAAAAANHxUUBq6KOXCJsADwAAAADSD/ZMhgMQJgtcAAcAAAAA0hJdDvxbSNUJHAADAAAAANI3H763vKonD3QAAgAAAADSRlsQLmJmTA2uAAEAAAAA0kZbEC5iZkwPrQABAAAAANJGWxAuYmZMDSwAAQAAAADSojIV/TMAwgFOAAMAAAAA0w2MywAVDqwI2gADAAAAANMNjMsAFQ6sDnMAAwAAAADTDYzLABUOrA3vAAIAAAAA0yB/7AYS2J0OagCZAAAAANMgf+wGEtidD60AGAAAAADTIH/sBhLYnQzrABYAAAAA0yB/7AYS2J0NZQAVAAAAANMgf+wGEtidDOMABAAAAADTIH/sBhLYnQ7tAAIAAAAA0yB/7AYS2J0O9AABAAAAANMgf+wGEtidD2IAAQAAAADTIH/sBhLYnQyqAAEAAAAA0yB/7AYS2J0NbQABAAAAANNFkfgQ6TqJD60AAgAAAADTZtJjUlBBCglcAAMAAAAA02bSY1JQQQoNLAABAAAAANN7ddSTPFRkAFIAAgAAAADT39F++IgBHQMcAAEAAAAA1W1yImwwlMQMogADAAAAANVzWRWbGL9vDOMAGQAAAADVc1kVmxi/bw5oAAIAAAAA1XNZFZsYv28PrQABAAAAANVzWRWbGL9vDWUAAQAAAADV7JanL92H3gyqAAIAAAAA1eyWpy/dh94OngABAAAAANX+/5x7DCXBDSQAAQAAAADWA+nypW8eeA2uAAEAAAAA1jSQ/0l24BoOsQACAAAAANavkHgBgwOTA5YABAAAAADWy3sCOYExcA0tAAIAAAAA18clfblz1cgAUgADAAAAANi0i1l4m77RAWEADgAAAADYtItZeJu+0QLbAAIAAAAA2Mvls0+h+rcDFAAGAAAAANjL5bNPofq3AFIAAgAAAADY3tEy/jnpgwyiAAMAAAAA2OCNR6qikEgCmgDNAAAAANjgjUeqopBIAZUAHgAAAADY4I1HqqKQSABSAA8AAAAA2OCNR6qikEgApgAGAAAAANjgjUeqopBIA1UAAgAAAADY4I1HqqKQSAOeAAIAAAAA2OCNR6qikEgASwABAAAAANjgjUeqopBIBuMAAQAAAADY4I1HqqKQSANdAAEAAAAA2OCNR6qikEgDlgABAAAAANjgjUeqopBIAxwAAQAAAADZI/jwM20pxA9ZABgAAAAA2SP48DNtKcQM4wADAAAAANnSr6VQ6wAAByQAEwAAAADZ0q+lUOsAAABSAAIAAAAA2dKvpVDrAAAC0wABAAAAANnSr6VQ6wAAAVoAAQAAAADZ0q+lUOsAAAKaAAEAAAAA2i3+RC/AnMYBTAAHAAAAANqYCjG9roXiDOMAAwAAAADamAoxva6F4gyiAAEAAAAA2pgKMb2uheINJAABAAAAANqYCjG9roXiDWUAAQAAAADamAoxva6F4g2mAAEAAAAA21XU/Krcd14OagAGAAAAANtV1Pyq3HdeDCgABQAAAADbVdT8qtx3Xg2uAAIAAAAA21xUixHHZDQJngACAAAAANv+oNAx1m+mBlIAAgAAAADcatETUN/Aqw5qAAEAAAAA3QB3UPNq/sUPrQAJAAAAAN0Ad1Dzav7FDKIAAwAAAADdAHdQ82r+xQ6eAAEAAAAA3QB3UPNq/sUOagABAAAAAN0Ad1Dzav7FDSwAAQAAAADdBQQ3CnDNkAGVAAIAAAAA3TB9JwLsdZsMogACAAAAAN6UQ7BN6HJ/CNoABAAAAADeyg7MUbjm9g2uAAwAAAAA3soOzFG45vYOcwACAAAAAN93K09hOUz+DSQAAgAAAADfdytPYTlM/g+tAAIAAAAA33crT2E5TP4NZQABAAAAAN93K09hOUz+DOsAAQAAAADfdytPYTlM/g5oAAEAAAAA34WIoD13JDYASwACAAAAAOA+WJQBqvUIDSQAAQAAAADgZYHuQHBLLg8/AAMAAAAA4KUlE2Wj3JIBlQACAAAAAOClJRNlo9ySAZcAAQAAAADgpSUTZaPckgIQAAEAAAAA4MPf4KL6gIMBlQABAAAAAODD3+Ci+oCDApoAAQAAAADg7veW4L3jYAMUAAMAAAAA4QrgVMinFeEBWgAFAAAAAOHWP7/9BC0JBqMADAAAAADh+PZXtIUz5wGVAAoAAAAA4uqfUbAcQtoM4wACAAAAAOMK06ec9WzjAxwAAgAAAADjqvHPbaFlnQ+tAA4AAAAA5PpKR9rvDukOpQACAAAAAOUjJff7Mu4OD60AAQAAAADlYnqu5f9apg70AAMAAAAA5WtHtc2t6v0AiQAFAAAAAOV2l87+5BGBDOMABgAAAADldpfO/uQRgQ2mAAEAAAAA5XaXzv7kEYENJAABAAAAAOV2l87+5BGBDWUAAQAAAADllsK1rvK37AzrAAMAAAAA5ZbCta7yt+wMqgACAAAAAOWfP6EC/WbBB5UAAgAAAADl9bAfPQP6CAbjAAIAAAAA5kK6T3mJP9MDngABAAAAAOaZdc0/2OiSD3YABQAAAADm7wVb1AgcLgVkAAIAAAAA56tXL8WReNoNJAABAAAAAOerVy/FkXjaD3YAAQAAAADn8OZT2kim8AyiAAIAAAAA6ChyVSBAeOwFZgACAAAAAOhn8KL522ZFApoAAgAAAADobtXaQVnh7AzrAAEAAAAA6J9VzVIBR0gNJAACAAAAAOifVc1SAUdIDa4AAQAAAADon1XNUgFHSAyiAAEAAAAA6VXSQAOS9g0C2wAGAAAAAOlc9zi7EHGkCJsAAQAAAADpaSBaqQAozwwoAAIAAAAA6Xu2KAYtWH8PrQAHAAAAAOl7tigGLVh/DSQAAgAAAADppIUVYBTfyQ6xAAIAAAAA6jAy6rnLYz4DlgACAAAAAOowMuq5y2M+ApoAAgAAAADqV/VaM1oEDw2uAAQAAAAA6lf1WjNaBA8M4wACAAAAAOpX9VozWgQPDGEAAQAAAADqd6ImoEHBiQzjAAEAAAAA6prpjdRqkfAC2wARAAAAAOqa6Y3UapHwAVoABAAAAADqzWxZlSmixQEHAAIAAAAA6uJix9Rj/BgPrQACAAAAAOsW1KoyznQ4DSQAAwAAAADrFtSqMs50OAzjAAEAAAAA6yiI32ZVDfMCmgABAAAAAOtFgMRaCFHBCVwAAQAAAADralr2HQ8tUg9ZAAgAAAAA62pa9h0PLVIMKAACAAAAAOtqWvYdDy1SCpsAAgAAAADr0W4dSDMQ9AzjAAUAAAAA7A+bIBkyPGQAlAAEAAAAAOwPmyAZMjxkBtEAAgAAAADsSs+7t/LOqQ2uAAIAAAAA7LBqBi9w504OagABAAAAAOywagYvcOdOD2IAAQAAAADszmNIkl8LmQDYAAIAAAAA7M7jtLAnkLgNJAAFAAAAAOzO47SwJ5C4DOMABQAAAADszuO0sCeQuA0sAAQAAAAA7M7jtLAnkLgPrQACAAAAAOzO47SwJ5C4DKoAAgAAAADszuO0sCeQuAxhAAEAAAAA7M7jtLAnkLgOagABAAAAAOzO47SwJ5C4D68AAQAAAADszuO0sCeQuA1lAAEAAAAA7M7jtLAnkLgMogABAAAAAOzkbBj9ScnXDOMAAwAAAADs5GwY/UnJ1w0sAAIAAAAA7ORsGP1JydcNbQABAAAAAOzkbBj9ScnXDOsAAQAAAADs5GwY/UnJ1w0kAAEAAAAA7ORsGP1JydcMKAABAAAAAOz2x4l7Mn7ZDOMAAgAAAADs9seJezJ+2Q+tAAEAAAAA7TWyPuL9x1UDlgAHAAAAAO1NQ8TiJCcJDOsAAQAAAADteOzFK+VIUAYRAAIAAAAA7cTla4F77pEJXAABAAAAAO7IgyqkKm6rAlEAAwAAAADu5UWTLZFrgQ+0AAMAAAAA7zAMeWWSIWIMogACAAAAAO+3D01qkmqCAU4ABQAAAADvtw9NapJqggGVAAEAAAAA7/ykcl/KORQLIwAKAAAAAO/8pHJfyjkUCJsAAgAAAADw4caONA0yQAFOAAQAAAAA8WDFY2IsKNYPPwACAAAAAPFlYqRKfSdCAU4ABAAAAADxfhFFexcQew+tAA0AAAAA8j69zkx4EMkN7wACAAAAAPK1r0SEZoWoDWUAAQAAAADyta9EhGaFqA+tAAEAAAAA8rWvRIRmhagNLAABAAAAAPK1r0SEZoWoDOsAAQAAAADyvkyF8DLpPQ2mAAEAAAAA8uGpWlZfqzEJGwABAAAAAPMJ/eTMuy59AQcAEAAAAADzF6CFqMPQ5w6XAAMAAAAA89OLuKwWO3kPrQAIAAAAAPPTi7isFjt5DmoABwAAAADz04u4rBY7eQ9iAAMAAAAA89OLuKwWO3kPWQABAAAAAPPTi7isFjt5DOsAAQAAAAD0KXxv/Nc0kQLTAAMAAAAA9EtpYeUz0cQC2wAhAAAAAPRLaWHlM9HEAtMABAAAAAD0S2lh5TPRxAGVAAQAAAAA9EtpYeUz0cQCmgACAAAAAPRLaWHlM9HEAFIAAgAAAAD0S2lh5TPRxAJRAAEAAAAA9EtpYeUz0cQBYQABAAAAAPRLaWHlM9HEA10AAQAAAAD0S2lh5TPRxAckAAEAAAAA9EtpYeUz0cQAzAABAAAAAPRLaWHlM9HEAlkAAQAAAAD0S2lh5TPRxAOWAAEAAAAA9GDM9Y0sFGEJGwABAAAAAPUsPG36hAPSCRsAAgAAAAD1Uu4Xd2WEKQkdADUAAAAA9VLuF3dlhCkOagAEAAAAAPVS7hd3ZYQpDt8AAwAAAAD1Uu4Xd2WEKQ9iAAMAAAAA9VLuF3dlhCkM4wACAAAAAPVS7hd3ZYQpDu0AAQAAAAD1Uu4Xd2WEKQ1lAAEAAAAA9VLuF3dlhCkNbQABAAAAAPVS7hd3ZYQpD60AAQAAAAD1Uu4Xd2WEKQ2mAAEAAAAA9XlLgx96QYwCmgAPAAAAAPV5S4MfekGMAxwAAQAAAAD1eUuDH3pBjAGVAAEAAAAA9XlLgx96QYwAnQABAAAAAPV5nMFvpM7sDmoAAQAAAAD1+i8ckFUpNQGVAAIAAAAA9f7nI6dWfzwMYQADAAAAAPZcZCyrh+B3AxwAFgAAAAD2XGQsq4fgdwGVAAMAAAAA9w00ZMI/PdsNrgABAAAAAPeIyxRbvf2xD60AAQAAAAD4Umamu8JsXwZSAAMAAAAA+QztjhyEUHUO7QAIAAAAAPnQDKSZacogDOMAEwAAAAD50AykmWnKIAxpABIAAAAA+dAMpJlpyiAMogALAAAAAPnQDKSZacogD1kACgAAAAD6QMRJeFQyOQjcAAIAAAAA+kkrOeFU26sPPwAEAAAAAPqup5Bx9VXLBtIAAgAAAAD6x9vWLG49rAJSAAIAAAAA+8go1Ld1wT0BBwACAAAAAPww9rPAoaStAFIAIAAAAAD8waHmoyeNaQjcAAQAAAAA/N3U05+LPuQBlQACAAAAAP3zgwRZbh0hD60AAQAAAAD+NHBJeW0TmALbAAUAAAAA/jRwSXltE5gJGgADAAAAAP6yS8BIzgl6AxwAAQAAAAD/kgKVe3M18g+tAAEAAAAA"
+
+// bookPlies limits how many plies into the game each difficulty may play from the book.
+var bookPlies = map[ai.Difficulty]int{
+	ai.DifficultyBeginner: 2,
+	ai.DifficultyEasy:     4,
+	ai.DifficultyMedium:   8,
+	ai.DifficultyHard:     10,
+	ai.DifficultyExpert:   12,
+}
+
+// loadOpeningBook reads the Polyglot file named by CHESS_BOOK, falling back to the embedded book.
+func loadOpeningBook() *openingBook {
+	if path := os.Getenv("CHESS_BOOK"); path != "" {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			if book, err := loadPolyglotBook(data); err == nil {
+				log.Printf("[DEBUG] Loaded opening book %s (%d entries)", path, len(book.entries))
+				return book
+			}
+		}
+		log.Printf("[ERROR] Could not load opening book %s, using embedded book", path)
+	}
+	data, err := base64.StdEncoding.DecodeString(embeddedOpeningBook)
+	if err != nil {
+		return nil
+	}
+	book, err := loadPolyglotBook(data)
+	if err != nil {
+		return nil
+	}
+	return book
+}
+
+// bookMove returns a book move for the live position if the current
+// difficulty still allows one.
+func (u *uiGame) bookMove() (engine.Move, bool) {
+	if u.book == nil || u.startFEN != "" || u.ply() >= bookPlies[u.difficulty] {
+		return engine.Move{}, false
+	}
+	return u.book.pickMove(u.g)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"go.rumenx.com/chess/engine"
+)
+
+const (
+	// captureBarHeight is the height of the strips above and below the
+	// board that show the captured pieces.
+	captureBarHeight = squareSize * 2 / 5
+	capturedIconSize = captureBarHeight - 4
+)
+
+// materialValues are the usual piece values in pawns.
+var materialValues = map[engine.PieceType]int{
+	engine.Pawn: 1, engine.Knight: 3, engine.Bishop: 3, engine.Rook: 5, engine.Queen: 9,
+}
+
+// capturedSet is what each side has taken, in the order the bars show it.
+type capturedSet struct {
+	taken   [2][]engine.PieceType // by White and by Black
+	balance int                   // White's material minus Black's, in pawns
+}
+
+// capturedCache remembers the moves a capturedSet was worked out for, so it
+// is only replayed when the shown position changes.
+type capturedCache struct {
+	start string
+	moves []engine.Move
+	set   capturedSet
+}
+
+// countCaptured replays moves from start and collects what each move takes.
+// A promotion adds the new piece less the pawn to the balance. The balance
+// starts from the material of the start position, which is uneven in odds
+// games.
+func countCaptured(start string, moves []engine.Move) (capturedSet, error) {
+	g, err := newGameFrom(start)
+	if err != nil {
+		return capturedSet{}, err
+	}
+	var set capturedSet
+	board := g.Board()
+	for sq := engine.Square(0); sq < 64; sq++ {
+		if p := board.GetPiece(sq); !p.IsEmpty() {
+			set.balance += sideSign(p.Color) * materialValues[p.Type]
+		}
+	}
+	for _, mv := range moves {
+		side := 0
+		if mv.Piece.Color == engine.Black {
+			side = 1
+		}
+		gain := 0
+		victim := g.Board().GetPiece(mv.To)
+		switch {
+		case !victim.IsEmpty() && victim.Color != mv.Piece.Color: // a Chess960 king may land on its own rook
+			set.taken[side] = append(set.taken[side], victim.Type)
+			gain = materialValues[victim.Type]
+		case victim.IsEmpty() && mv.Piece.Type == engine.Pawn && mv.From.File() != mv.To.File(): // en passant
+			set.taken[side] = append(set.taken[side], engine.Pawn)
+			gain = 1
+		}
+		if mv.Type == engine.Promotion {
+			gain += materialValues[mv.Promotion] - 1
+		}
+		set.balance += sideSign(mv.Piece.Color) * gain
+		if g, err = playMove(g, start, mv); err != nil {
+			return set, err
+		}
+	}
+	for _, taken := range set.taken {
+		sort.SliceStable(taken, func(i, j int) bool { return materialValues[taken[i]] < materialValues[taken[j]] })
+	}
+	return set, nil
+}
+
+// sideSign is 1 for White and -1 for Black.
+func sideSign(c engine.Color) int {
+	if c == engine.Black {
+		return -1
+	}
+	return 1
+}
+
+// updateCaptured recounts the captured pieces when the displayed position
+// changes, whether by a move, an undo or stepping through the game.
+func (u *uiGame) updateCaptured() {
+	moves := u.history()[:u.displayPly()]
+	c := &u.captured
+	if c.moves != nil && c.start == u.startFEN && equalMoves(c.moves, moves) {
+		return
+	}
+	set, err := countCaptured(u.startFEN, moves)
+	if err != nil {
+		log.Printf("[ERROR] Counting captured pieces: %v", err)
+	}
+	c.start, c.moves, c.set = u.startFEN, append([]engine.Move{}, moves...), set
+}
+
+// equalMoves reports whether a and b are the same moves in the same order.
+func equalMoves(a, b []engine.Move) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !sameMove(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// drawCaptured fills the bars above and below the board. Each bar belongs to
+// the player on that side of the board and shows the pieces they took, with
+// their lead in material.
+func (u *uiGame) drawCaptured(screen *ebiten.Image) {
+	bar := ebiten.NewImage(boardPixels+evalBarWidth, captureBarHeight)
+	bar.Fill(color.RGBA{0x22, 0x22, 0x22, 0xFF})
+	screen.DrawImage(bar, &ebiten.DrawImageOptions{GeoM: translate(0, 0)})
+	screen.DrawImage(bar, &ebiten.DrawImageOptions{GeoM: translate(0, boardTop+boardPixels)})
+
+	top, bottom := engine.Black, engine.White
+	if !u.whiteAtBottom {
+		top, bottom = engine.White, engine.Black
+	}
+	u.drawCapturedRow(screen, top, 2)
+	u.drawCapturedRow(screen, bottom, boardTop+boardPixels+2)
+}
+
+// drawCapturedRow draws the pieces taken by side c at y, pieces of a kind
+// overlapping.
+func (u *uiGame) drawCapturedRow(screen *ebiten.Image, c engine.Color, y int) {
+	side, victims := 0, engine.Black
+	if c == engine.Black {
+		side, victims = 1, engine.White
+	}
+	scale := float64(capturedIconSize) / squareSize
+	x := 6
+	for i, t := range u.captured.set.taken[side] {
+		if i > 0 {
+			if t == u.captured.set.taken[side][i-1] {
+				x += capturedIconSize / 2
+			} else {
+				x += capturedIconSize
+			}
+		}
+		op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+		op.GeoM.Scale(scale, scale)
+		op.GeoM.Translate(float64(x), float64(y))
+		screen.DrawImage(u.pieceImage(engine.Piece{Type: t, Color: victims}), op)
+	}
+	if lead := sideSign(c) * u.captured.set.balance; lead > 0 {
+		if len(u.captured.set.taken[side]) > 0 {
+			x += capturedIconSize + 4
+		}
+		drawText(screen, fmt.Sprintf("+%d", lead), panelFace, x, y+(capturedIconSize-lineHeight)/2, color.RGBA{0xC8, 0xC8, 0xC8, 0xFF})
+	}
+}
@@ -0,0 +1,547 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"math/rand"
+	"slices"
+	"strings"
+
+	"go.rumenx.com/chess/engine"
+)
+
+// chess960BackRank returns White's back rank (a to h) for start position n
+// (0-959) using Scharnagl's numbering.
+func chess960BackRank(n int) string {
+	var rank [8]byte
+	rank[n%4*2+1] = 'B' // light-squared bishop on b, d, f or h
+	n /= 4
+	rank[n%4*2] = 'B' // dark-squared bishop on a, c, e or g
+	n /= 4
+	place := func(piece byte, nth int) {
+		for f := range rank {
+			if rank[f] != 0 {
+				continue
+			}
+			if nth == 0 {
+				rank[f] = piece
+				return
+			}
+			nth--
+		}
+	}
+	place('Q', n%6)
+	n /= 6
+	knights := [10][2]int{{0, 1}, {0, 2}, {0, 3}, {0, 4}, {1, 2}, {1, 3}, {1, 4}, {2, 3}, {2, 4}, {3, 4}}
+	// the second knight's index counts the free squares left after the first
+	place('N', knights[n][0])
+	place('N', knights[n][1]-1)
+	place('R', 0)
+	place('K', 0)
+	place('R', 0)
+	return string(rank[:])
+}
+
+// chess960FEN returns the X-FEN of start position n.
+func chess960FEN(n int) string {
+	back := chess960BackRank(n)
+	return strings.ToLower(back) + "/pppppppp/8/8/8/8/PPPPPPPP/" + back + " w KQkq - 0 1"
+}
+
+// castlingRooks returns the squares of the rooks that keep castling rights.
+// It accepts standard, X-FEN (KQkq plus file letters) and Shredder-FEN (file letters).
+func castlingRooks(pos fenPosition) ([]int, error) {
+	if pos.castling == "-" {
+		return nil, nil
+	}
+	var rooks []int
+	for _, c := range pos.castling {
+		row, king, rook := 0, byte('K'), byte('R')
+		if c >= 'a' && c <= 'z' {
+			row, king, rook = 56, 'k', 'r'
+		}
+		kingFile := -1
+		for f := 0; f < 8; f++ {
+			if pos.board[row+f] == king {
+				kingFile = f
+			}
+		}
+		if kingFile < 0 {
+			return nil, errBadFEN
+		}
+		file := -1
+		switch lc := c | 0x20; {
+		case lc == 'k':
+			for f := 7; f > kingFile && file < 0; f-- {
+				if pos.board[row+f] == rook {
+					file = f
+				}
+			}
+		case lc == 'q':
+			for f := 0; f < kingFile && file < 0; f++ {
+				if pos.board[row+f] == rook {
+					file = f
+				}
+			}
+		case lc >= 'a' && lc <= 'h':
+			file = int(lc - 'a')
+		}
+		if file < 0 || pos.board[row+file] != rook {
+			return nil, errBadFEN
+		}
+		rooks = append(rooks, row+file)
+	}
+	return rooks, nil
+}
+
+// formatCastling renders castling rights White first, king side first.
+// X-FEN uses K/Q unless another rook stands further out on that side;
+// Shredder-FEN always names the rook file.
+func formatCastling(pos fenPosition, rooks []int, shredder bool) string {
+	var b strings.Builder
+	for _, row := range []int{0, 56} {
+		king, rook := byte('K'), byte('R')
+		if row == 56 {
+			king, rook = 'k', 'r'
+		}
+		kingFile := 0
+		for f := 0; f < 8; f++ {
+			if pos.board[row+f] == king {
+				kingFile = f
+			}
+		}
+		for _, kingSide := range []bool{true, false} {
+			for _, sq := range rooks {
+				f := sq - row
+				if f < 0 || f > 7 || (f > kingFile) != kingSide {
+					continue
+				}
+				outermost := true
+				for o := 0; o < 8; o++ {
+					if pos.board[row+o] == rook && o != f && (o > kingFile) == kingSide && (o > f) == kingSide {
+						outermost = false
+					}
+				}
+				c := byte('a' + f)
+				if !shredder && outermost {
+					c = 'q'
+					if kingSide {
+						c = 'k'
+					}
+				}
+				if row == 0 {
+					c -= 0x20
+				}
+				b.WriteByte(c)
+			}
+		}
+	}
+	if b.Len() == 0 {
+		return "-"
+	}
+	return b.String()
+}
+
+// convertFEN rewrites the castling field of fen as X-FEN or Shredder-FEN.
+func convertFEN(fen string, shredder bool) (string, error) {
+	pos, err := parseFEN(fen)
+	if err != nil {
+		return "", err
+	}
+	rooks, err := castlingRooks(pos)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(fen)
+	for len(fields) < 6 {
+		fields = append(fields, []string{"-", "-", "0", "1"}[len(fields)-2])
+	}
+	fields[2] = formatCastling(pos, rooks, shredder)
+	return strings.Join(fields, " "), nil
+}
+
+// castlingRookSquare returns the rook a castling move castles with, so the
+// move can be entered by clicking the king onto that rook. The app's own
+// Chess960 castles are encoded as the king taking its rook; the engine's
+// standard castles by the king's target square.
+func castlingRookSquare(g *engine.Game, mv engine.Move) (engine.Square, bool) {
+	if p := g.Board().GetPiece(mv.To); !p.IsEmpty() && p.Type == engine.Rook && p.Color == mv.Piece.Color {
+		return mv.To, true
+	}
+	pos, err := parseFEN(g.ToFEN())
+	if err != nil {
+		return 0, false
+	}
+	rooks, err := castlingRooks(pos)
+	if err != nil {
+		return 0, false
+	}
+	kingSide := mv.To.File() > 4
+	for _, sq := range rooks {
+		if sq/8 == mv.From.Rank() && (sq%8 > mv.From.File()) == kingSide {
+			return engine.Square(sq), true
+		}
+	}
+	return 0, false
+}
+
+// Chess960 castling. The engine only knows standard castling, so in games
+// from any other setup it is handed positions without castling rights and
+// the app generates and plays the castles itself. Such a castle is a move
+// of type engine.Castling from the king onto its rook; it starts a new
+// engine game from the position after it, and uiGame.before keeps the moves
+// that led there.
+
+var errNoCastle = errors.New("not a legal castling move")
+
+// appCastling reports whether the app rather than the engine castles in
+// games from start: the king or a castling rook starts off its standard square.
+func appCastling(start string) bool {
+	if start == "" {
+		return false
+	}
+	pos, err := parseFEN(start)
+	if err != nil {
+		return false
+	}
+	rooks, err := castlingRooks(pos)
+	if err != nil {
+		return false
+	}
+	for _, sq := range rooks {
+		row, king := 0, byte('K')
+		if sq >= 56 {
+			row, king = 56, 'k'
+		}
+		if pos.board[row+4] != king || (sq%8 != 0 && sq%8 != 7) {
+			return true
+		}
+	}
+	return false
+}
+
+// engineFEN returns fen as given to the engine: without castling rights
+// when the app castles.
+func engineFEN(fen string) string {
+	if !appCastling(fen) {
+		return fen
+	}
+	fields := strings.Fields(fen)
+	fields[2] = "-"
+	return strings.Join(fields, " ")
+}
+
+// castlingRightsAfter returns the rooks that may still castle after moves
+// from start: a king move loses both, a rook moving or taken loses its own.
+func castlingRightsAfter(start string, moves []engine.Move) []int {
+	pos, err := parseFEN(start)
+	if err != nil {
+		return nil
+	}
+	rooks, err := castlingRooks(pos)
+	if err != nil {
+		return nil
+	}
+	for _, mv := range moves {
+		rooks = slices.DeleteFunc(rooks, func(sq int) bool {
+			if mv.Piece.Type == engine.King && (sq < 8) == (mv.Piece.Color == engine.White) {
+				return true
+			}
+			return sq == int(mv.From) || sq == int(mv.To)
+		})
+	}
+	return rooks
+}
+
+// castleSquares returns where the king and the rook end up when the king on
+// from castles with the rook on rook: the g and f files on the king side,
+// the c and d files on the queen side.
+func castleSquares(from, rook int) (kingTo, rookTo int) {
+	row := from - from%8
+	if rook > from {
+		return row + 6, row + 5
+	}
+	return row + 2, row + 3
+}
+
+// chess960Castles returns the castling moves of the side to move in g, the
+// position after history from start, when the app castles. Every square
+// the king and the rook cross or land on must be empty but for those two,
+// and none the king stands on, crosses or lands on may be attacked.
+func chess960Castles(g *engine.Game, start string, history []engine.Move) []engine.Move {
+	if !appCastling(start) {
+		return nil
+	}
+	pos, err := parseFEN(g.ToFEN())
+	if err != nil {
+		return nil
+	}
+	row, king, color := 0, byte('K'), engine.White
+	if !pos.whiteToMove {
+		row, king, color = 56, 'k', engine.Black
+	}
+	from := slices.Index(pos.board[row:row+8], king)
+	if from < 0 {
+		return nil
+	}
+	from += row
+	var moves []engine.Move
+	for _, rook := range castlingRightsAfter(start, history) {
+		if rook-rook%8 != row {
+			continue
+		}
+		kingTo, rookTo := castleSquares(from, rook)
+		board := pos.board
+		board[from], board[rook] = 0, 0
+		if !emptyBetween(board, from, kingTo) || !emptyBetween(board, rook, rookTo) {
+			continue
+		}
+		safe := true
+		for sq := min(from, kingTo); sq <= max(from, kingTo) && safe; sq++ {
+			safe = !attacked(board, sq, !pos.whiteToMove)
+		}
+		if safe {
+			moves = append(moves, engine.Move{
+				From:  engine.Square(from),
+				To:    engine.Square(rook),
+				Piece: engine.Piece{Type: engine.King, Color: color},
+				Type:  engine.Castling,
+			})
+		}
+	}
+	return moves
+}
+
+// emptyBetween reports whether the squares from a to b, both included, of
+// one rank are empty.
+func emptyBetween(board [64]byte, a, b int) bool {
+	for sq := min(a, b); sq <= max(a, b); sq++ {
+		if board[sq] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// attacked reports whether sq is attacked on board by White's pieces, or
+// by Black's when byWhite is false.
+func attacked(board [64]byte, sq int, byWhite bool) bool {
+	piece := func(c byte) byte {
+		if byWhite {
+			return c
+		}
+		return c + 'a' - 'A'
+	}
+	f, r := sq%8, sq/8
+	at := func(df, dr int) byte {
+		if f+df < 0 || f+df > 7 || r+dr < 0 || r+dr > 7 {
+			return 0
+		}
+		return board[(r+dr)*8+f+df]
+	}
+	pawnRow := -1 // a white pawn attacks from the rank below
+	if !byWhite {
+		pawnRow = 1
+	}
+	if at(-1, pawnRow) == piece('P') || at(1, pawnRow) == piece('P') {
+		return true
+	}
+	for _, d := range [8][2]int{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}} {
+		if at(d[0], d[1]) == piece('N') {
+			return true
+		}
+	}
+	for _, d := range [8][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}, {1, 1}, {1, -1}, {-1, 1}, {-1, -1}} {
+		if at(d[0], d[1]) == piece('K') {
+			return true
+		}
+		slider := piece('R')
+		if d[0] != 0 && d[1] != 0 {
+			slider = piece('B')
+		}
+		for n := 1; ; n++ {
+			c := at(n*d[0], n*d[1])
+			if c == piece('Q') || c == slider {
+				return true
+			}
+			if c != 0 || f+n*d[0] < 0 || f+n*d[0] > 7 || r+n*d[1] < 0 || r+n*d[1] > 7 {
+				break
+			}
+		}
+	}
+	return false
+}
+
+// castleChess960 plays a castling move of chess960Castles on a copy of g
+// and returns the position after it as a new engine game.
+func castleChess960(g *engine.Game, mv engine.Move) (*engine.Game, error) {
+	pos, err := parseFEN(g.ToFEN())
+	if err != nil {
+		return nil, err
+	}
+	from, rook := int(mv.From), int(mv.To)
+	kingTo, rookTo := castleSquares(from, rook)
+	k, r := pos.board[from], pos.board[rook]
+	pos.board[from], pos.board[rook] = 0, 0
+	pos.board[kingTo], pos.board[rookTo] = k, r
+	side, fullmove := "b", pos.fullmove
+	if !pos.whiteToMove {
+		side, fullmove = "w", fullmove+1
+	}
+	return engine.FromFEN(pos.placement() + " " + side + " - - " + stringFromInt(pos.halfmove+1) + " " + stringFromInt(fullmove))
+}
+
+// castleSAN returns the SAN of castling move mv, given the game after it.
+func castleSAN(after *engine.Game, mv engine.Move) string {
+	san := "O-O-O"
+	if mv.To > mv.From {
+		san = "O-O"
+	}
+	switch {
+	case inCheck(after) && len(after.GetAllLegalMoves()) == 0:
+		san += "#"
+	case inCheck(after):
+		san += "+"
+	}
+	return san
+}
+
+// playMove plays mv in g, a game from start, and returns the game holding
+// the new position: g itself, or a new game after a Chess960 castle.
+func playMove(g *engine.Game, start string, mv engine.Move) (*engine.Game, error) {
+	if mv.Type == engine.Castling && appCastling(start) {
+		return castleChess960(g, mv)
+	}
+	return g, g.MakeMove(mv)
+}
+
+// history returns the moves of the live game, Chess960 castles included.
+func (u *uiGame) history() []engine.Move {
+	return append(slices.Clip(u.before), u.history()...)
+}
+
+// ply returns the number of half-moves played in the live game.
+func (u *uiGame) ply() int { return len(u.before) + u.ply() }
+
+// gameSAN returns the SAN of the live game's moves.
+func (u *uiGame) gameSAN() []string {
+	return append(slices.Clip(u.beforeSAN), u.g.GenerateSAN()...)
+}
+
+// chess960Castles returns the castling moves the app plays in the live game.
+func (u *uiGame) chess960Castles() []engine.Move {
+	return chess960Castles(u.g, u.startFEN, u.history())
+}
+
+// allLegalMoves returns the engine's legal moves in the live game and the
+// Chess960 castles.
+func (u *uiGame) allLegalMoves() []engine.Move {
+	return append(u.g.GetAllLegalMoves(), u.chess960Castles()...)
+}
+
+// makeMove plays mv in the live game.
+func (u *uiGame) makeMove(mv engine.Move) error {
+	if mv.Type != engine.Castling || !appCastling(u.startFEN) {
+		return u.g.MakeMove(mv)
+	}
+	if !slices.ContainsFunc(u.chess960Castles(), func(c engine.Move) bool { return c.From == mv.From && c.To == mv.To }) {
+		return errNoCastle
+	}
+	after, err := castleChess960(u.g, mv)
+	if err != nil {
+		return err
+	}
+	u.before = append(u.history(), mv)
+	u.beforeSAN = append(u.gameSAN(), castleSAN(after, mv))
+	u.g = after
+	return nil
+}
+
+// undoMove takes back the last move of the live game. Undoing a Chess960
+// castle replays the game up to it.
+func (u *uiGame) undoMove() error {
+	if u.ply() > 0 || len(u.before) == 0 {
+		_, err := u.g.UndoMove()
+		return err
+	}
+	moves := u.before[:len(u.before)-1]
+	g, err := newGameFrom(u.startFEN)
+	if err != nil {
+		return err
+	}
+	u.g, u.before, u.beforeSAN = g, nil, nil
+	for _, mv := range moves {
+		if err := u.makeMove(mv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startChess960 starts a new Chess960 game from position n, or a random one when n < 0.
+func (u *uiGame) startChess960(n int) {
+	if n < 0 {
+		n = rand.Intn(960)
+	}
+	u.chess960 = true
+	u.odds, u.puzzle, u.drill, u.endgame = oddsNone, nil, nil, nil
+	u.chess960Pos = (n%960 + 960) % 960
+	u.startFEN = chess960FEN(u.chess960Pos)
+	u.resetGame(u.playerColor)
+}
+
+// toggleChess960 switches between Chess960 and the standard start position.
+func (u *uiGame) toggleChess960() {
+	if u.chess960 {
+		u.chess960 = false
+		u.startFEN = ""
+		u.resetGame(u.playerColor)
+		return
+	}
+	u.startChess960(-1)
+}
+
+// exportFEN saves the displayed position as X-FEN, or Shredder-FEN when shredder is set.
+func (u *uiGame) exportFEN(shredder bool) {
+	fen := u.displayGame().ToFEN()
+	if appCastling(u.startFEN) {
+		// the engine's FEN has no castling rights; fill in the app's
+		if fields := strings.Fields(fen); len(fields) > 2 {
+			fields[2] = "-"
+			if rooks := castlingRightsAfter(u.startFEN, u.history()[:u.displayPly()]); len(rooks) > 0 {
+				pos, _ := parseFEN(fen)
+				fields[2] = formatCastling(pos, rooks, false)
+			}
+			fen = strings.Join(fields, " ")
+		}
+	}
+	fen, err := convertFEN(fen, shredder)
+	if err != nil {
+		u.flashMsg(tr("Export failed"))
+		return
+	}
+	where, err := saveTextFile("go-chess-position.fen", fen+"\n")
+	if err != nil {
+		u.flashMsg(tr("Export failed"))
+		return
+	}
+	u.flashMsg(trf("Saved %s", where))
+}
+
+// loadStartupFEN applies a start position given at launch (X-FEN or Shredder-FEN).
+func (u *uiGame) loadStartupFEN() {
+	fen := startupFEN()
+	if fen == "" {
+		return
+	}
+	xfen, err := convertFEN(fen, false)
+	if err == nil {
+		_, err = engine.FromFEN(engineFEN(xfen))
+	}
+	if err != nil {
+		log.Printf("[ERROR] Ignoring start position %q: %v", fen, err)
+		return
+	}
+	u.startFEN = xfen
+	u.resetGame(u.playerColor)
+}
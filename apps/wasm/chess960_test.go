@@ -0,0 +1,138 @@
+package main
+
+import (
+	"testing"
+
+	"go.rumenx.com/chess/engine"
+)
+
+func square(t *testing.T, s string) engine.Square {
+	t.Helper()
+	sq, ok := parseSquare(s)
+	if !ok {
+		t.Fatalf("bad square %q", s)
+	}
+	return engine.Square(sq)
+}
+
+func TestAppCastling(t *testing.T) {
+	tests := []struct {
+		fen  string
+		want bool
+	}{
+		{"", false},
+		{chess960FEN(518), false}, // the standard setup
+		{chess960FEN(0), true},
+		{"r2k4/8/8/8/8/8/8/R2K4 w Qq - 0 1", true},
+		{"4k3/8/8/8/8/8/8/4K3 w - - 0 1", false},
+	}
+	for _, tt := range tests {
+		if got := appCastling(tt.fen); got != tt.want {
+			t.Errorf("appCastling(%q) = %v, want %v", tt.fen, got, tt.want)
+		}
+	}
+}
+
+// TestChess960Castling plays the moves, looks for the castle of the king onto
+// the given rook and checks where both pieces end up.
+func TestChess960Castling(t *testing.T) {
+	tests := []struct {
+		name       string
+		fen        string
+		moves      []string // UCI moves played before castling
+		rook       string   // the rook the king castles with
+		king, dest string   // where the king and the rook end up, "" when castling is illegal
+		san        string
+	}{
+		{"position 0, king onto the h-rook", chess960FEN(0), []string{"f2f4", "f7f5", "f1f3", "f8f6"}, "h1", "g1", "f1", "O-O"},
+		{"position 0, f-rook in the way", chess960FEN(0), nil, "h1", "", "", ""},
+		{"position 0, king moved", chess960FEN(0), []string{"f2f4", "f7f5", "f1f3", "f8f6", "g1f2", "g8f7", "f2g1", "f7g8"}, "h1", "", "", ""},
+		{"rook onto the king's square", "r2k4/8/8/8/8/8/8/R2K4 w Qq - 0 1", nil, "a1", "c1", "d1", "O-O-O+"},
+		{"king lands on an attacked square", "2r1k3/8/8/8/8/8/8/R2K4 w Q - 0 1", nil, "a1", "", "", ""},
+		{"black castles", "rk6/8/8/8/8/8/8/RK6 b Qq - 0 1", nil, "a8", "c8", "d8", "O-O-O"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := newGameFrom(tt.fen)
+			if err != nil {
+				t.Fatalf("newGameFrom(%q): %v", tt.fen, err)
+			}
+			var history []engine.Move
+			for _, uci := range tt.moves {
+				mv, ok := findUCIMove(g, uci)
+				if !ok {
+					t.Fatalf("%s is not legal in %s", uci, g.ToFEN())
+				}
+				if g, err = playMove(g, tt.fen, mv); err != nil {
+					t.Fatalf("playMove(%s): %v", uci, err)
+				}
+				history = append(history, mv)
+			}
+			rook := square(t, tt.rook)
+			var castle *engine.Move
+			for _, mv := range chess960Castles(g, tt.fen, history) {
+				if mv.To == rook {
+					castle = &mv
+				}
+			}
+			if tt.king == "" {
+				if castle != nil {
+					t.Fatalf("castling onto %s allowed in %s", tt.rook, g.ToFEN())
+				}
+				return
+			}
+			if castle == nil {
+				t.Fatalf("no castling move onto %s in %s", tt.rook, g.ToFEN())
+			}
+			after, err := playMove(g, tt.fen, *castle)
+			if err != nil {
+				t.Fatalf("playMove(castling): %v", err)
+			}
+			if san := castleSAN(after, *castle); san != tt.san {
+				t.Errorf("SAN %q, want %q", san, tt.san)
+			}
+			board := after.Board()
+			if p := board.GetPiece(square(t, tt.king)); p.Type != engine.King || p.Color != castle.Piece.Color {
+				t.Errorf("%s holds %v, want the king", tt.king, p)
+			}
+			if p := board.GetPiece(square(t, tt.dest)); p.Type != engine.Rook || p.Color != castle.Piece.Color {
+				t.Errorf("%s holds %v, want the rook", tt.dest, p)
+			}
+			if tt.rook != tt.king && tt.rook != tt.dest && !board.GetPiece(rook).IsEmpty() {
+				t.Errorf("%s is not empty after castling", tt.rook)
+			}
+			if castle.Piece.Color == after.ActiveColor() {
+				t.Errorf("%v is still to move after castling", castle.Piece.Color)
+			}
+		})
+	}
+}
+
+// TestAttacked checks the attack test that castling relies on.
+func TestAttacked(t *testing.T) {
+	tests := []struct {
+		fen     string
+		sq      string
+		byWhite bool
+		want    bool
+	}{
+		{"4k3/8/8/8/8/8/8/R3K3 w - - 0 1", "a8", true, true},    // rook along the file
+		{"4k3/8/8/8/8/8/P7/R3K3 w - - 0 1", "a8", true, false},  // blocked by a pawn
+		{"4k3/8/8/8/8/8/8/4K2B w - - 0 1", "a8", true, true},    // bishop along the long diagonal
+		{"4k3/8/8/8/8/8/3p4/4K3 w - - 0 1", "e1", false, true},  // black pawn
+		{"4k3/8/8/8/8/8/4p3/4K3 w - - 0 1", "e1", false, false}, // pawns don't attack straight ahead
+		{"4k3/8/8/8/8/5n2/8/4K3 w - - 0 1", "e1", false, true},  // knight
+		{"4k3/8/8/8/8/8/8/4K3 w - - 0 1", "d2", true, true},     // king
+		{"4k3/8/8/8/8/8/8/4K3 w - - 0 1", "c3", true, false},    // nothing
+	}
+	for _, tt := range tests {
+		pos, err := parseFEN(tt.fen)
+		if err != nil {
+			t.Fatalf("parseFEN(%q): %v", tt.fen, err)
+		}
+		sq, _ := parseSquare(tt.sq)
+		if got := attacked(pos.board, sq, tt.byWhite); got != tt.want {
+			t.Errorf("attacked(%s, %s, white=%v) = %v, want %v", tt.fen, tt.sq, tt.byWhite, got, tt.want)
+		}
+	}
+}
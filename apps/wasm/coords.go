@@ -0,0 +1,42 @@
+package main
+
+import (
+	"image/color"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const coordinatesSettingKey = "coordinates"
+
+// toggleCoordinates shows or hides the file and rank labels and remembers the choice.
+func (u *uiGame) toggleCoordinates() {
+	u.toggleFlag(coordinatesSettingKey, &u.showCoords, "Coordinates on", "Coordinates off")
+}
+
+// drawCoordinates labels the files along the bottom edge and the ranks
+// along the left edge, in the colour of the other square shade.
+func (u *uiGame) drawCoordinates(board *ebiten.Image) {
+	if !u.showCoords {
+		return
+	}
+	ink := func(file, vrank int) color.Color {
+		if (vrank+file)%2 == 1 {
+			return lightSquare
+		}
+		return darkSquare
+	}
+	h := int(smallTextSize * 1.2)
+	for file := 0; file < 8; file++ {
+		label := string(rune('a' + file))
+		x := (file+1)*squareSize - textWidth(label, smallFace) - 3
+		drawText(board, label, smallFace, x, boardPixels-h-2, ink(file, 7))
+	}
+	for vrank := 0; vrank < 8; vrank++ {
+		rank := vrank + 1
+		if u.whiteAtBottom {
+			rank = 8 - vrank
+		}
+		drawText(board, strconv.Itoa(rank), smallFace, 3, vrank*squareSize+2, ink(0, vrank))
+	}
+}
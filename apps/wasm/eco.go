@@ -0,0 +1,105 @@
+package main
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+
+	"go.rumenx.com/chess/engine"
+)
+
+// ecoTSV lists ECO code, opening name and position (FEN board, side and
+// castling fields), derived from the lichess chess-openings data set (CC0).
+//
+//go:embed eco.tsv
+var ecoTSV string
+
+// maxECOPlies bounds the replay when classifying; no named line is longer.
+const maxECOPlies = 40
+
+// ecoOpening is a named opening from the ECO table.
+type ecoOpening struct {
+	code string
+	name string
+}
+
+func (o ecoOpening) String() string { return o.code + " " + o.name }
+
+var (
+	ecoOnce  sync.Once
+	ecoIndex map[string]ecoOpening
+)
+
+// ecoKey reduces a FEN to the fields used as ECO table key.
+func ecoKey(fen string) string {
+	fields := strings.Fields(fen)
+	if len(fields) > 3 {
+		fields = fields[:3]
+	}
+	return strings.Join(fields, " ")
+}
+
+// lookupECO returns the opening whose position matches fen exactly.
+func lookupECO(fen string) (ecoOpening, bool) {
+	ecoOnce.Do(func() {
+		lines := strings.Split(ecoTSV, "\n")
+		ecoIndex = make(map[string]ecoOpening, len(lines))
+		for _, line := range lines[1:] {
+			cols := strings.Split(line, "\t")
+			if len(cols) != 3 {
+				continue
+			}
+			ecoIndex[cols[2]] = ecoOpening{code: cols[0], name: cols[1]}
+		}
+	})
+	o, ok := ecoIndex[ecoKey(fen)]
+	return o, ok
+}
+
+// classifyOpening replays the game and returns the last named opening
+// reached, so the name survives moves that leave the table.
+func classifyOpening(moves []engine.Move) (ecoOpening, bool) {
+	if len(moves) > maxECOPlies {
+		moves = moves[:maxECOPlies]
+	}
+	g := engine.NewGame()
+	var found ecoOpening
+	ok := false
+	for _, mv := range moves {
+		if err := g.MakeMove(mv); err != nil {
+			break
+		}
+		if o, hit := lookupECO(g.ToFEN()); hit {
+			found, ok = o, true
+		}
+	}
+	return found, ok
+}
+
+// updateOpening refreshes the opening name whenever the ply count changes
+// (moves and undo alike).
+func (u *uiGame) updateOpening() {
+	history := u.history()
+	if u.openingPly == len(history) {
+		return
+	}
+	u.openingPly = len(history)
+	if u.startFEN != "" {
+		// ECO names only describe games from the standard start position
+		u.opening = nil
+		return
+	}
+	if o, ok := classifyOpening(history); ok {
+		u.opening = &o
+	} else {
+		u.opening = nil
+	}
+}
+
+// openingLines returns the panel lines for the current opening, wrapped to the panel width.
+func (u *uiGame) openingLines() []string {
+	if u.opening == nil {
+		return nil
+	}
+	return wrapText(trf("Opening: %s", u.opening), panelTextWidth)
+}
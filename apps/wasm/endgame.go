@@ -0,0 +1,258 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+
+	"go.rumenx.com/chess/ai"
+	"go.rumenx.com/chess/engine"
+)
+
+const endgameSettingKey = "endgames.json"
+
+// endgameGoal is what the player has to achieve in an endgame drill.
+type endgameGoal int
+
+const (
+	goalMate endgameGoal = iota // checkmate within the move limit
+	// goalTablebaseWin is met by promoting into a position the tablebase
+	// reports won for the player within the move limit; when no table can
+	// judge it, a promotion that keeps its queen is enough.
+	goalTablebaseWin
+	goalHold // avoid losing for the move limit
+)
+
+// endgamePosition is one drill of the endgame library. The player takes the
+// side to move; limit counts the player's moves.
+type endgamePosition struct {
+	name  string
+	fen   string
+	goal  endgameGoal
+	limit int
+}
+
+var endgamePositions = []endgamePosition{
+	{"K+Q vs K", "8/8/8/3k4/8/8/8/4KQ2 w - - 0 1", goalMate, 15},
+	{"K+R vs K", "8/8/8/4k3/8/8/8/R3K3 w - - 0 1", goalMate, 25},
+	{"Lucena", "1K1k4/1P6/8/8/8/8/r7/2R5 w - - 0 1", goalTablebaseWin, 20},
+	{"Philidor", "3k4/8/r7/3PK3/8/8/8/7R b - - 0 1", goalHold, 20},
+	{"K+P vs K: win", "4k3/8/4K3/8/4P3/8/8/8 w - - 0 1", goalTablebaseWin, 20},
+	{"K+P vs K: hold", "8/4k3/8/4P3/4K3/8/8/8 b - - 0 1", goalHold, 20},
+}
+
+func (p endgamePosition) goalText() string {
+	switch p.goal {
+	case goalMate:
+		return trf("Mate within %d moves", p.limit)
+	case goalTablebaseWin:
+		return trf("Reach a tablebase win within %d moves", p.limit)
+	}
+	return trf("Hold the draw for %d moves", p.limit)
+}
+
+// endgameSession is the drill being played in endgame mode. The opponent is
+// the AI at full strength whatever difficulty is selected.
+type endgameSession struct {
+	ai     ai.Engine
+	passed map[string]bool
+	idx    int
+	ply    int // last ply checked
+	tbPly  int // last ply whose tablebase result was checked
+	done   bool
+	result string
+}
+
+// sideCounts counts the pieces of one side by upper-case letter.
+func sideCounts(pos fenPosition, white bool) map[byte]int {
+	n := map[byte]int{}
+	for _, c := range pos.board {
+		if c == 0 {
+			continue
+		}
+		if isWhite := c >= 'A' && c <= 'Z'; isWhite != white {
+			continue
+		}
+		if c >= 'a' {
+			c -= 'a' - 'A'
+		}
+		n[c]++
+	}
+	return n
+}
+
+// toggleEndgames enters or leaves endgame drill mode.
+func (u *uiGame) toggleEndgames() {
+	if u.endgame != nil {
+		u.endgame = nil
+		u.startFEN = ""
+		u.resetGame(u.playerColor)
+		u.flashMsg(tr("Endgame drills off"))
+		return
+	}
+	s := &endgameSession{ai: ai.NewMinimaxAI(ai.DifficultyExpert), passed: map[string]bool{}}
+	if data, ok := loadSetting(endgameSettingKey); ok {
+		_ = json.Unmarshal([]byte(data), &s.passed)
+	}
+	u.puzzle, u.drill, u.chess960, u.odds = nil, nil, false, oddsNone
+	u.endgame = s
+	u.startEndgame(0)
+}
+
+// startEndgame sets up drill i of the library (wrapping around).
+func (u *uiGame) startEndgame(i int) {
+	s := u.endgame
+	s.idx = (i%len(endgamePositions) + len(endgamePositions)) % len(endgamePositions)
+	s.ply, s.tbPly, s.done, s.result = -1, -1, false, ""
+	p := endgamePositions[s.idx]
+	u.startFEN = p.fen
+	u.resetGame(u.playerColor)
+	u.playerColor = u.g.ActiveColor()
+	u.whiteAtBottom = u.playerColor == engine.White
+	u.flashMsg(tr(p.name) + ": " + p.goalText())
+}
+
+// finishEndgame records the outcome of the current drill once.
+func (u *uiGame) finishEndgame(passed bool, why string) {
+	s := u.endgame
+	s.done = true
+	if !passed {
+		s.result = trf("Failed: %s", why)
+		u.flashMsg(trf("%s. Shift+N = retry", s.result))
+		return
+	}
+	s.result = trf("Passed: %s", why)
+	s.passed[endgamePositions[s.idx].name] = true
+	data, err := json.Marshal(s.passed)
+	if err == nil {
+		err = saveSetting(endgameSettingKey, string(data))
+	}
+	if err != nil {
+		log.Printf("[ERROR] Saving endgame progress: %v", err)
+	}
+	u.flashMsg(trf("%s. N = next", s.result))
+}
+
+// updateEndgame checks the success criteria once per ply. With tablebases a
+// move that gives away the result fails at once; without them the drill is
+// judged on the board.
+func (u *uiGame) updateEndgame() {
+	s := u.endgame
+	if s == nil || s.done {
+		return
+	}
+	p := endgamePositions[s.idx]
+	if u.tbWDLPly >= 0 && u.tbWDLPly != s.tbPly {
+		s.tbPly = u.tbWDLPly
+		wdl := u.tbWDL
+		if s.tbPly%2 == 1 {
+			wdl = -wdl // the opponent is to move
+		}
+		switch {
+		case p.goal != goalHold && wdl < wdlWin:
+			u.finishEndgame(false, tr("the win slipped away"))
+			return
+		case p.goal == goalHold && wdl == wdlLoss:
+			u.finishEndgame(false, tr("that position is lost"))
+			return
+		case p.goal == goalTablebaseWin && wdl == wdlWin && s.tbPly%2 == 1 && u.hasQueen(u.playerColor):
+			u.finishEndgame(true, trf("tablebase win in %d moves", (s.tbPly+1)/2))
+			return
+		}
+	}
+	ply := u.ply()
+	if p.goal == goalTablebaseWin && u.tbUnknown(ply) && u.g.ActiveColor() == u.playerColor && u.hasQueen(u.playerColor) {
+		// no table could judge the promotion, so the board has to
+		u.finishEndgame(true, trf("promoted in %d moves", (ply+1)/2))
+		return
+	}
+	if ply == s.ply {
+		return
+	}
+	s.ply = ply
+	pos, err := parseFEN(u.g.ToFEN())
+	if err != nil {
+		return
+	}
+	white := u.playerColor == engine.White
+	mine, theirs := sideCounts(pos, white), sideCounts(pos, !white)
+	noMoves := len(u.allLegalMoves()) == 0
+	mated := noMoves && inCheck(u.g)
+	myTurn := u.g.ActiveColor() == u.playerColor
+	moves := (ply + 1) / 2 // the player moves first
+	switch p.goal {
+	case goalMate:
+		switch {
+		case mated && !myTurn:
+			u.finishEndgame(true, trf("mate in %d", moves))
+		case noMoves:
+			u.finishEndgame(false, tr("stalemate"))
+		case mine['Q']+mine['R'] == 0:
+			u.finishEndgame(false, tr("no mating material left"))
+		case moves >= p.limit:
+			u.finishEndgame(false, trf("no mate in %d moves", p.limit))
+		}
+	case goalTablebaseWin:
+		switch {
+		case mated:
+			u.finishEndgame(!myTurn, tr("checkmate"))
+		case noMoves:
+			u.finishEndgame(false, tr("stalemate"))
+		case mine['P']+mine['Q'] == 0:
+			u.finishEndgame(false, tr("the pawn is gone"))
+		case moves >= p.limit && mine['Q'] == 0:
+			u.finishEndgame(false, trf("no promotion in %d moves", p.limit))
+		}
+	case goalHold:
+		switch {
+		case mated:
+			u.finishEndgame(!myTurn, tr("checkmate"))
+		case !myTurn && theirs['Q'] > 0:
+			u.finishEndgame(false, tr("the pawn promoted"))
+		case noMoves:
+			u.finishEndgame(true, tr("stalemate"))
+		case theirs['P']+theirs['Q'] == 0:
+			u.finishEndgame(true, tr("the pawn is gone"))
+		case moves >= p.limit && !myTurn:
+			u.finishEndgame(true, trf("held for %d moves", p.limit))
+		}
+	}
+	if !s.done && p.goal != goalHold && myTurn && p.limit-moves == 2 {
+		u.playSound(soundLowTime) // two moves left
+	}
+}
+
+// tbUnknown reports whether the tablebase has finished with the position
+// after ply moves without a result: no tables, a missing table or a failed
+// probe.
+func (u *uiGame) tbUnknown(ply int) bool {
+	return u.tbPly == ply && !u.tbPending && u.tbWDLPly != ply
+}
+
+// hasQueen reports whether side has a queen on the live board.
+func (u *uiGame) hasQueen(side engine.Color) bool {
+	pos, err := parseFEN(u.g.ToFEN())
+	return err == nil && sideCounts(pos, side == engine.White)['Q'] > 0
+}
+
+// endgameLines returns the panel lines for endgame mode.
+func (u *uiGame) endgameLines() []string {
+	s := u.endgame
+	if s == nil {
+		return nil
+	}
+	p := endgamePositions[s.idx]
+	lines := []string{
+		trf("Endgame %d/%d: %s", s.idx+1, len(endgamePositions), tr(p.name)),
+		p.goalText(),
+	}
+	if s.done {
+		return append(lines, wrapText(s.result, panelTextWidth)...)
+	}
+	passed := 0
+	for _, q := range endgamePositions {
+		if s.passed[q.name] {
+			passed++
+		}
+	}
+	return append(lines, trf("Move %d, passed %d", (u.ply()+2)/2, passed))
+}
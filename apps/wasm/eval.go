@@ -0,0 +1,279 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"go.rumenx.com/chess/engine"
+)
+
+const (
+	evalBarWidth = 24
+	// mateScore is the search score of a checkmate; mates found sooner score higher.
+	mateScore   = 100000
+	evalDepth   = 2
+	graphHeight = 64
+)
+
+// evalResult is a position score from White's point of view.
+type evalResult struct {
+	cp   int // centipawns, meaningful when mate == 0
+	mate int // moves until mate: >0 White mates, <0 Black mates
+	over bool
+}
+
+// label returns a compact score such as "+1.3", "M2" or "-M1".
+func (e evalResult) label() string {
+	switch {
+	case e.over && e.cp == 0:
+		return "1/2"
+	case e.over:
+		return "#"
+	case e.mate > 0:
+		return "M" + fmtInt(e.mate)
+	case e.mate < 0:
+		return "-M" + fmtInt(-e.mate)
+	}
+	sign := "+"
+	cp := e.cp
+	if cp < 0 {
+		sign = "-"
+		cp = -cp
+	}
+	if cp >= 1000 {
+		return sign + fmtInt(cp/100)
+	}
+	return sign + fmtInt(cp/100) + "." + fmtInt(cp%100/10)
+}
+
+// whiteShare maps the score to the fraction of the bar owned by White.
+func (e evalResult) whiteShare() float64 {
+	switch {
+	case e.mate > 0 || (e.over && e.cp > 0):
+		return 1
+	case e.mate < 0 || (e.over && e.cp < 0):
+		return 0
+	}
+	return 1 / (1 + math.Exp(-float64(e.cp)/400))
+}
+
+// graphValue clamps the score to [-1, 1] for plotting.
+func (e evalResult) graphValue() float64 {
+	return 2*e.whiteShare() - 1
+}
+
+// newGameFrom returns a game at fen, or at the standard start position when
+// fen is empty. Chess960 castling rights stay with the app (see engineFEN).
+func newGameFrom(fen string) (*engine.Game, error) {
+	if fen == "" {
+		return engine.NewGame(), nil
+	}
+	return engine.FromFEN(engineFEN(fen))
+}
+
+// replayMoves rebuilds a game from the start position (see newGameFrom) and the given moves.
+func replayMoves(start string, moves []engine.Move) (*engine.Game, error) {
+	g, err := newGameFrom(start)
+	if err != nil {
+		return nil, err
+	}
+	for _, mv := range moves {
+		if g, err = playMove(g, start, mv); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// evaluatePosition runs a shallow search on g and converts the result to White's point of view.
+func evaluatePosition(g *engine.Game) evalResult {
+	score := negamax(g, evalDepth, -mateScore-1, mateScore+1, 0)
+	white := score
+	if g.ActiveColor() == engine.Black {
+		white = -score
+	}
+	res := evalResult{cp: white, over: len(g.GetAllLegalMoves()) == 0}
+	if abs := absInt(score); abs > mateScore-100 {
+		moves := (mateScore - abs + 1) / 2
+		if white < 0 {
+			moves = -moves
+		}
+		res.mate = moves
+	}
+	return res
+}
+
+// negamax returns the score for the side to move, using Game.Evaluate at the leaves.
+func negamax(g *engine.Game, depth, alpha, beta, ply int) int {
+	moves := g.GetAllLegalMoves()
+	if len(moves) == 0 {
+		if inCheck(g) {
+			return -(mateScore - ply)
+		}
+		return 0
+	}
+	if depth == 0 {
+		s := g.Evaluate()
+		if g.ActiveColor() == engine.Black {
+			s = -s
+		}
+		return s
+	}
+	best := -mateScore - 1
+	for _, mv := range moves {
+		if err := g.MakeMove(mv); err != nil {
+			continue
+		}
+		s := -negamax(g, depth-1, -beta, -alpha, ply+1)
+		_, _ = g.UndoMove()
+		if s > best {
+			best = s
+		}
+		if s > alpha {
+			alpha = s
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	return best
+}
+
+// inCheck reports whether the side to move is in check.
+func inCheck(g *engine.Game) bool {
+	switch g.Status() {
+	case engine.Check, engine.WhiteWins, engine.BlackWins: // a win can only be a checkmate
+		return true
+	}
+	return false
+}
+
+// updateEvalHistory evaluates, one position at a time in the background,
+// every ply of the game that has no score yet.
+func (u *uiGame) updateEvalHistory() {
+	if u.evalPending {
+		return
+	}
+	history := u.history()
+	if len(u.evalHist) > len(history) {
+		u.evalHist = u.evalHist[:len(history)+1]
+	}
+	idx := len(u.evalHist)
+	if idx > len(history) {
+		return
+	}
+	moves := append([]engine.Move(nil), history[:idx]...)
+	gen := u.evalGen
+	start := u.startFEN
+	u.evalPending = true
+	go func() {
+		defer func() { u.evalPending = false }()
+		pos, err := replayMoves(start, moves)
+		if err != nil {
+			return
+		}
+		res := evaluatePosition(pos)
+		if gen != u.evalGen || len(u.evalHist) != idx {
+			return
+		}
+		u.evalHist = append(u.evalHist, res)
+	}()
+}
+
+// resetEvalHistory drops cached scores from ply onwards (after undo or a new game).
+func (u *uiGame) resetEvalHistory(ply int) {
+	u.evalGen++
+	if ply < len(u.evalHist) {
+		u.evalHist = u.evalHist[:ply]
+	}
+}
+
+// currentEval returns the score of the displayed position, if known.
+func (u *uiGame) currentEval() (evalResult, bool) {
+	ply := u.displayPly()
+	if ply < len(u.evalHist) {
+		return u.evalHist[ply], true
+	}
+	if ply > 0 && ply-1 < len(u.evalHist) {
+		// keep showing the previous score until the new one arrives
+		return u.evalHist[ply-1], true
+	}
+	return evalResult{}, false
+}
+
+func (u *uiGame) drawEvalBar(screen *ebiten.Image) {
+	x, top := float32(boardPixels), float32(boardTop)
+	vector.DrawFilledRect(screen, x, top, evalBarWidth, boardPixels, color.RGBA{0x40, 0x40, 0x40, 0xFF}, false)
+	res, ok := u.currentEval()
+	share := 0.5
+	if ok {
+		share = res.whiteShare()
+	}
+	whiteH := float32(share * boardPixels)
+	if u.whiteAtBottom {
+		vector.DrawFilledRect(screen, x, top+boardPixels-whiteH, evalBarWidth, whiteH, color.RGBA{0xF0, 0xF0, 0xF0, 0xFF}, false)
+	} else {
+		vector.DrawFilledRect(screen, x, top, evalBarWidth, whiteH, color.RGBA{0xF0, 0xF0, 0xF0, 0xFF}, false)
+	}
+	vector.StrokeLine(screen, x, top+boardPixels/2, x+evalBarWidth, top+boardPixels/2, 1, color.RGBA{0xC0, 0x40, 0x40, 0xFF}, false)
+	if !ok {
+		return
+	}
+	label := res.label()
+	// print the score on the side that is ahead
+	whiteAhead := share >= 0.5
+	y := boardTop + 4
+	if whiteAhead == u.whiteAtBottom {
+		y = boardTop + boardPixels - 4 - int(math.Ceil(smallTextSize*1.2))
+	}
+	tx := boardPixels + (evalBarWidth-textWidth(label, smallFace))/2
+	var ink color.Color = color.White
+	if whiteAhead {
+		ink = color.RGBA{0x1A, 0x1A, 0x1A, 0xFF} // on the light part of the bar
+	}
+	drawText(screen, label, smallFace, tx, y, ink)
+}
+
+// graphRect returns the panel-relative rectangle of the evaluation graph,
+// which sits above the key help.
+func graphRect() (x, y, w, h int) {
+	return 8, helpTop() - 8 - graphHeight, panelWidth - 16, graphHeight
+}
+
+func (u *uiGame) drawEvalGraph(screen *ebiten.Image) {
+	gx, gy, gw, gh := graphRect()
+	x0 := float32(panelX + gx)
+	y0 := float32(gy)
+	vector.DrawFilledRect(screen, x0, y0, float32(gw), float32(gh), color.RGBA{0x33, 0x33, 0x33, 0xFF}, false)
+	mid := y0 + float32(gh)/2
+	vector.StrokeLine(screen, x0, mid, x0+float32(gw), mid, 1, color.RGBA{0x77, 0x77, 0x77, 0xFF}, false)
+	n := len(u.evalHist)
+	if n < 2 {
+		drawText(screen, tr("Eval graph"), smallFace, int(x0)+4, gy+2, color.White)
+		return
+	}
+	step := float32(gw) / float32(n-1)
+	point := func(i int) (float32, float32) {
+		v := float32(u.evalHist[i].graphValue())
+		return x0 + step*float32(i), mid - v*float32(gh)/2
+	}
+	for i := 1; i < n; i++ {
+		ax, ay := point(i - 1)
+		bx, by := point(i)
+		vector.StrokeLine(screen, ax, ay, bx, by, 1.5, color.RGBA{0xE0, 0xE0, 0xE0, 0xFF}, true)
+	}
+	if ply := u.displayPly(); ply < n {
+		cx, cy := point(ply)
+		vector.StrokeLine(screen, cx, y0, cx, y0+float32(gh), 1, color.RGBA{0x5A, 0x78, 0xC8, 0xFF}, false)
+		vector.DrawFilledCircle(screen, cx, cy, 3, color.RGBA{0x5A, 0x78, 0xC8, 0xFF}, true)
+	}
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,19 @@
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// saveTextFile writes content to name in the working directory.
+func saveTextFile(name, content string) (string, error) {
+	if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+		return "", err
+	}
+	if abs, err := filepath.Abs(name); err == nil {
+		return abs, nil
+	}
+	return name, nil
+}
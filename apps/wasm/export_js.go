@@ -0,0 +1,22 @@
+//go:build js && wasm
+
+package main
+
+import "syscall/js"
+
+// saveTextFile offers content to the browser as a file download.
+func saveTextFile(name, content string) (string, error) {
+	doc := js.Global().Get("document")
+	urlAPI := js.Global().Get("URL")
+	blob := js.Global().Get("Blob").New([]any{content}, map[string]any{"type": "text/plain"})
+	href := urlAPI.Call("createObjectURL", blob)
+	a := doc.Call("createElement", "a")
+	a.Set("href", href)
+	a.Set("download", name)
+	doc.Get("body").Call("appendChild", a)
+	a.Call("click")
+	a.Call("remove")
+	// revoke after the click has been processed, otherwise some browsers cancel the download
+	js.Global().Call("setTimeout", urlAPI.Get("revokeObjectURL").Call("bind", urlAPI, href), 0)
+	return name, nil
+}
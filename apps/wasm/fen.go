@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// fenPosition is a parsed FEN record. Squares use the engine layout (a1=0 ... h8=63)
+// and hold the FEN piece letter, or 0 when empty.
+type fenPosition struct {
+	board       [64]byte
+	whiteToMove bool
+	castling    string // "-" or a subset of "KQkq"
+	epSquare    int    // -1 when there is no en passant target
+	halfmove    int
+	fullmove    int
+}
+
+var errBadFEN = errors.New("invalid FEN")
+
+// parseFEN parses the board, side, castling and en passant fields of fen;
+// the move counters are optional.
+func parseFEN(fen string) (fenPosition, error) {
+	pos := fenPosition{epSquare: -1, castling: "-", fullmove: 1}
+	fields := strings.Fields(fen)
+	if len(fields) < 2 {
+		return pos, errBadFEN
+	}
+	ranks := strings.Split(fields[0], "/")
+	if len(ranks) != 8 {
+		return pos, errBadFEN
+	}
+	for i, row := range ranks {
+		rank := 7 - i
+		file := 0
+		for _, c := range row {
+			switch {
+			case c >= '1' && c <= '8':
+				file += int(c - '0')
+			case strings.ContainsRune("pnbrqkPNBRQK", c):
+				if file > 7 {
+					return pos, errBadFEN
+				}
+				pos.board[rank*8+file] = byte(c)
+				file++
+			default:
+				return pos, errBadFEN
+			}
+		}
+		if file != 8 {
+			return pos, errBadFEN
+		}
+	}
+	switch fields[1] {
+	case "w":
+		pos.whiteToMove = true
+	case "b":
+	default:
+		return pos, errBadFEN
+	}
+	if len(fields) > 2 {
+		pos.castling = fields[2]
+	}
+	if len(fields) > 3 && fields[3] != "-" {
+		sq, ok := parseSquare(fields[3])
+		if !ok {
+			return pos, errBadFEN
+		}
+		pos.epSquare = sq
+	}
+	if len(fields) > 5 {
+		pos.halfmove, _ = strconv.Atoi(fields[4])
+		pos.fullmove, _ = strconv.Atoi(fields[5])
+	}
+	return pos, nil
+}
+
+// placement returns the piece placement field of the position.
+func (p fenPosition) placement() string {
+	var b strings.Builder
+	for rank := 7; rank >= 0; rank-- {
+		empty := 0
+		for file := 0; file < 8; file++ {
+			c := p.board[rank*8+file]
+			if c == 0 {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				b.WriteByte(byte('0' + empty))
+				empty = 0
+			}
+			b.WriteByte(c)
+		}
+		if empty > 0 {
+			b.WriteByte(byte('0' + empty))
+		}
+		if rank > 0 {
+			b.WriteByte('/')
+		}
+	}
+	return b.String()
+}
+
+// parseSquare converts algebraic coordinates such as "e4" to a square index.
+func parseSquare(s string) (int, bool) {
+	if len(s) != 2 || s[0] < 'a' || s[0] > 'h' || s[1] < '1' || s[1] > '8' {
+		return 0, false
+	}
+	return int(s[1]-'1')*8 + int(s[0]-'a'), true
+}
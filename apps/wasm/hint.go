@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"image/color"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"go.rumenx.com/chess/ai"
+	"go.rumenx.com/chess/engine"
+)
+
+var (
+	hintArrowColor   = color.RGBA{0x2E, 0x9E, 0x4F, 0xC0}
+	threatArrowColor = color.RGBA{0xD0, 0x30, 0x30, 0xC0}
+)
+
+// requestHint runs the current AI engine on the player's behalf and stores
+// the suggested move so it can be drawn as an arrow.
+func (u *uiGame) requestHint() {
+	if u.aiPending || u.hintPending {
+		u.flashMsg(tr("AI thinking"))
+		return
+	}
+	if len(u.allLegalMoves()) == 0 {
+		u.flashMsg(tr("No moves available"))
+		return
+	}
+	ply := u.ply()
+	if u.hintMove != nil && u.hintPly == ply {
+		return
+	}
+	pos, err := engine.FromFEN(u.g.ToFEN())
+	if err != nil {
+		u.flashMsg(tr("No hint available"))
+		return
+	}
+	u.hintPending = true
+	u.flashMsg(tr("Looking for a hint..."))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	eng := ai.NewMinimaxAI(u.difficulty) // its own engine: the game's may be searching too
+	gen := u.evalGen
+	go func() {
+		defer cancel()
+		mv, err := eng.GetBestMove(ctx, pos)
+		u.hintPending = false
+		if gen != u.evalGen || u.ply() != ply {
+			return // an undo or a new game: the hint is for another position
+		}
+		if err != nil {
+			u.flashMsg(tr("No hint available"))
+			return
+		}
+		u.hintMove = &mv
+		u.hintPly = ply
+		u.flashMsg(trf("Hint: %s-%s", mv.From, mv.To))
+	}()
+}
+
+// toggleThreat switches the null-move threat arrow on or off.
+func (u *uiGame) toggleThreat() {
+	u.showThreat = !u.showThreat
+	u.threatMove = nil
+	u.threatPly = -1
+	if u.showThreat {
+		u.flashMsg(tr("Threat view on"))
+	} else {
+		u.flashMsg(tr("Threat view off"))
+	}
+}
+
+// updateThreat searches the null-move position (same board, opponent to
+// move) once per ply while the threat view is enabled.
+func (u *uiGame) updateThreat() {
+	if !u.showThreat || u.threatPending {
+		return
+	}
+	ply := u.ply()
+	if u.threatPly == ply {
+		return
+	}
+	u.threatPly = ply
+	u.threatMove = nil
+	if inCheck(u.g) {
+		// passing is illegal in check, so there is no threat to show
+		return
+	}
+	pos, err := engine.FromFEN(nullMoveFEN(u.g.ToFEN()))
+	if err != nil {
+		return
+	}
+	u.threatPending = true
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	eng := ai.NewMinimaxAI(u.difficulty) // its own engine, as for hints
+	go func() {
+		defer cancel()
+		mv, err := eng.GetBestMove(ctx, pos)
+		u.threatPending = false
+		if err != nil || u.threatPly != ply {
+			return
+		}
+		u.threatMove = &mv
+	}()
+}
+
+// clearHints drops hint and threat arrows after the position changes.
+func (u *uiGame) clearHints() {
+	u.hintMove = nil
+	u.threatMove = nil
+	u.threatPly = -1
+}
+
+func (u *uiGame) drawHints(screen *ebiten.Image) {
+	ply := u.ply()
+	if u.showThreat && u.threatMove != nil && u.threatPly == ply {
+		u.drawArrow(screen, u.threatMove.From, u.threatMove.To, threatArrowColor)
+	}
+	if u.hintMove != nil && u.hintPly == ply {
+		u.drawArrow(screen, u.hintMove.From, u.hintMove.To, hintArrowColor)
+	}
+}
+
+// nullMoveFEN returns fen with the side to move swapped and the en passant
+// square cleared, i.e. the position after a "pass".
+func nullMoveFEN(fen string) string {
+	fields := strings.Fields(fen)
+	if len(fields) < 2 {
+		return fen
+	}
+	if fields[1] == "w" {
+		fields[1] = "b"
+	} else {
+		fields[1] = "w"
+	}
+	if len(fields) > 3 {
+		fields[3] = "-"
+	}
+	return strings.Join(fields, " ")
+}
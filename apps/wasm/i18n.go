@@ -0,0 +1,583 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pieceLetters is the catalog key of the SAN piece letters, translated
+// letter for letter in the order king, queen, rook, bishop, knight.
+const pieceLetters = "KQRBN"
+
+// messages is the catalog chain for the user's locales, most specific first.
+// English is the last resort: a key without a translation is shown as is.
+var messages = catalogChain(userLocales())
+
+// catalogChain picks the catalogs for locales such as "de-AT" or
+// "es_ES.UTF-8", trying the regional catalog before the language one. An
+// English locale ends the chain: the keys are English, so the user prefers
+// them to any locale listed after it.
+func catalogChain(locales []string) []map[string]string {
+	var chain []map[string]string
+	for _, l := range locales {
+		l = strings.ToLower(strings.ReplaceAll(l, "_", "-"))
+		if i := strings.IndexAny(l, ".@"); i >= 0 {
+			l = l[:i]
+		}
+		tags := []string{l}
+		if i := strings.IndexByte(l, '-'); i >= 0 {
+			tags = append(tags, l[:i])
+		}
+		for _, tag := range tags {
+			if c, ok := catalogs[tag]; ok {
+				chain = append(chain, c)
+			}
+		}
+		if tags[len(tags)-1] == "en" {
+			break
+		}
+	}
+	return chain
+}
+
+// tr translates a UI string; the English text is the key.
+func tr(msg string) string {
+	for _, c := range messages {
+		if s, ok := c[msg]; ok {
+			return s
+		}
+	}
+	return msg
+}
+
+// trf translates a format string and fills it in.
+func trf(format string, args ...any) string {
+	return fmt.Sprintf(tr(format), args...)
+}
+
+// untranslated stands in for tr where the text must stay English, such as
+// PGN tags.
+func untranslated(msg string) string { return msg }
+
+// localizeSAN swaps the English piece letters of SAN for the display
+// language's: "Nf3" is "Sf3" in German.
+func localizeSAN(san string) string {
+	return swapPieceLetters(san, pieceLetters, tr(pieceLetters))
+}
+
+// delocalizeSAN turns SAN typed with the display language's piece letters
+// back into English.
+func delocalizeSAN(san string) string {
+	return swapPieceLetters(san, tr(pieceLetters), pieceLetters)
+}
+
+func swapPieceLetters(san, from, to string) string {
+	if from == to || len(from) != len(to) {
+		return san
+	}
+	b := []byte(san)
+	for i, c := range b {
+		if j := strings.IndexByte(from, c); j >= 0 {
+			b[i] = to[j]
+		}
+	}
+	return string(b)
+}
+
+// catalogs maps a lower-case language tag to its translations. Panel text
+// has to fit the panel: about 37 characters a line, 19 in the wide buttons
+// and 15 in the narrow ones.
+var catalogs = map[string]map[string]string{
+	"de": {
+		pieceLetters: "KDTLS",
+
+		// panel
+		"Human vs AI":                          "Mensch vs KI",
+		"Human vs Human":                       "Mensch vs Mensch",
+		"Undo (U)":                             "Zurück (U)",
+		"Hint (H)":                             "Tipp (H)",
+		"Threat (T)":                           "Drohung (T)",
+		"Color:":                               "Farbe:",
+		"White":                                "Weiß",
+		"Black":                                "Schwarz",
+		"(locked)":                             "(gesperrt)",
+		"Difficulty:":                          "Spielstärke:",
+		"harmless":                             "harmlos",
+		"easy":                                 "leicht",
+		"normal":                               "normal",
+		"hard":                                 "schwer",
+		"godlike":                              "göttlich",
+		"Status: %s":                           "Status: %s",
+		"Turn: %s":                             "Am Zug: %s",
+		"Player: %s":                           "Spieler: %s",
+		"Diff: %s":                             "Stärke: %s",
+		"Moves: %d":                            "Züge: %d",
+		"Chess960 #%d":                         "Chess960 Nr. %d",
+		"Handicap: %s":                         "Vorgabe: %s",
+		"%s odds by %s":                        "%s-Vorgabe von %s",
+		"AI %s/move":                           "KI %s/Zug",
+		"Pieces: %s":                           "Figuren: %s",
+		"Msg: %s":                              "Info: %s",
+		"Moves":                                "Züge",
+		"Eval: %s cp":                          "Bewertung: %s cp",
+		"Eval graph":                           "Bewertungsverlauf",
+		"Opening: %s":                          "Eröffnung: %s",
+		"Sound: off":                           "Ton: aus",
+		"Sound: %d%%":                          "Ton: %d%%",
+		"Move> ":                               "Zug> ",
+		"in_progress":                          "läuft",
+		"check":                                "Schach",
+		"white_wins":                           "Weiß gewinnt",
+		"black_wins":                           "Schwarz gewinnt",
+		"draw":                                 "Remis",
+		"stalemate":                            "Patt",
+		"checkmate":                            "Schachmatt",
+		"Normal":                               "Normal",
+		"Discs":                                "Scheiben",
+		"Opponent hidden":                      "Gegner verdeckt",
+		"Blindfold":                            "Blind",
+		"None":                                 "Keine",
+		"Pawn":                                 "Bauer",
+		"Knight":                               "Springer",
+		"Bishop":                               "Läufer",
+		"Rook":                                 "Turm",
+		"Queen":                                "Dame",
+		"King":                                 "König",
+		"TB: Draw":                             "TB: Remis",
+		"TB: Win for %s":                       "TB: Gewinn für %s",
+		"TB: Loss for %s":                      "TB: Verlust für %s",
+		"TB: Draw (50-move rule)":              "TB: Remis (50-Züge-Regel)",
+		"TB: Win in %d for %s":                 "TB: %[2]s gewinnt in %[1]d",
+		"TB: Loss in %d for %s":                "TB: %[2]s verliert in %[1]d",
+		"/=type V=pieces Z=peek Tab M=mute":    "/=Zug V=Figuren Z=zeigen Tab M=Ton",
+		"9=960 [ ]=pos C=fen O=odds L=ai time": "9=960 [ ]=Pos C=FEN O=Vorg. L=KI-Zeit",
+		"H=hint T=threat R=report X=pgn K=♘":   "H=Tipp T=Drohung R=Bericht X=PGN K=♘",
+		"Keys: N=new A=mode F=flip E=eval U=undo": "N=Neu A=Modus F=Drehen E=Wert U=Rück",
+		"P=puzzles D=drill G=endgames I=coords":   "P=Aufgaben D=Training G=Endspiele I=Koord.",
+
+		// messages
+		"Mode: %s":                              "Modus: %s",
+		"Difficulty: %s":                        "Spielstärke: %s",
+		"Back to live position":                 "Zurück zur aktuellen Stellung",
+		"Illegal move":                          "Unzulässiger Zug",
+		"No undo while training":                "Kein Zurücknehmen im Training",
+		"No move to undo":                       "Kein Zug zum Zurücknehmen",
+		"Undid your last move":                  "Dein letzter Zug wurde zurückgenommen",
+		"Undid move":                            "Zug zurückgenommen",
+		"New game (%s)":                         "Neue Partie (%s)",
+		"Viewing ply %d, click board to return": "Halbzug %d, Brett klicken für zurück",
+		"AI thinking":                           "KI denkt nach",
+		"No moves available":                    "Keine Züge möglich",
+		"No hint available":                     "Kein Tipp verfügbar",
+		"Looking for a hint...":                 "Suche einen Tipp...",
+		"Hint: %s-%s":                           "Tipp: %s-%s",
+		"Threat view on":                        "Drohungen an",
+		"Threat view off":                       "Drohungen aus",
+		"Coordinates on":                        "Koordinaten an",
+		"Coordinates off":                       "Koordinaten aus",
+		"Premove %s-%s":                         "Vorauszug %s-%s",
+		"Premove %s-%s is illegal, premoves cancelled": "Vorauszug %s-%s ist nicht erlaubt, Vorauszüge gelöscht",
+		"Premoves cancelled":                           "Vorauszüge gelöscht",
+		"Pick the promotion piece":                     "Umwandlungsfigur wählen",
+		"Confirm":                                      "Bestätigen",
+		"Confirm moves":                                "Züge bestätigen",
+		"Confirm moves on":                             "Züge bestätigen an",
+		"Confirm moves off":                            "Züge bestätigen aus",
+		"Auto-queen":                                   "Auto-Dame",
+		"Auto-queen on":                                "Auto-Dame an",
+		"Auto-queen off":                               "Auto-Dame aus",
+		"Targets":                                      "Zielfelder",
+		"Show legal targets":                           "Zielfelder zeigen",
+		"Legal targets shown":                          "Zielfelder sichtbar",
+		"Legal targets hidden":                         "Zielfelder verborgen",
+		"Click %s again to play, elsewhere to cancel": "%s erneut klicken zum Ziehen, woanders zum Abbrechen",
+		"Move cancelled": "Zug abgebrochen",
+		"Ask for a second click on the target before a move is played": "Vor dem Ziehen einen zweiten Klick auf das Zielfeld verlangen",
+		"Promote to a queen without asking":                            "Ohne Nachfrage in eine Dame umwandeln",
+		"Mark the squares the selected piece can move to":              "Die Felder markieren, auf die die gewählte Figur ziehen kann",
+		"Export failed":                  "Export fehlgeschlagen",
+		"Saved %s":                       "Gespeichert: %s",
+		"Odds: %s":                       "Vorgabe: %s",
+		"AI time: %s":                    "KI-Zeit: %s",
+		"AI time: %s from the next game": "KI-Zeit: %s ab der nächsten Partie",
+		"Pieces: %s (hold Z to reveal)":  "Figuren: %s (Z halten zum Zeigen)",
+
+		// analysis
+		"No moves to analyse":                "Keine Züge zum Analysieren",
+		"Analysis complete (R=report X=PGN)": "Analyse fertig (R=Bericht X=PGN)",
+		"Analysis running":                   "Analyse läuft",
+		"Analysing move %d/%d...":            "Analysiere Zug %d/%d...",
+		"Game report":                        "Partiebericht",
+		"Accuracy":                           "Genauigkeit",
+		"Best":                               "Bester",
+		"Good":                               "Gut",
+		"Inaccuracy":                         "Ungenau",
+		"Mistake":                            "Fehler",
+		"Blunder":                            "Patzer",
+		"Key moments:":                       "Schlüsselmomente:",
+		"best %s":                            "besser %s",
+		"R=close  X=export annotated PGN":    "R=schließen  X=kommentierte PGN",
+
+		// puzzles and repertoire
+		"Puzzle mode off":                       "Aufgaben aus",
+		"No puzzles available":                  "Keine Aufgaben vorhanden",
+		"Puzzle: find the best move for %s":     "Aufgabe: finde den besten Zug für %s",
+		"Wrong move (%d), try again":            "Falscher Zug (%d), nochmal",
+		"Wrong move, try again":                 "Falscher Zug, nochmal",
+		"Solved (not counted). N = next puzzle": "Gelöst (ohne Wertung). N = nächste",
+		"Solved! +%d. N = next puzzle":          "Gelöst! +%d. N = nächste Aufgabe",
+		"Your move":                             "Du bist am Zug",
+		"Solved":                                "Gelöst",
+		"Failed, keep trying":                   "Falsch, weiter versuchen",
+		"Puzzle %s (%d)":                        "Aufgabe %s (%d)",
+		"Rating %d streak %d best %d":           "Wertung %d Serie %d beste %d",
+		"Repertoire drill off":                  "Repertoire-Training aus",
+		"No repertoire loaded":                  "Kein Repertoire geladen",
+		"Drill: play your prepared moves as %s": "Training: spiele deine Züge als %s",
+		"%s is not in your repertoire: %s":      "%s ist nicht im Repertoire: %s",
+		"Line complete. N = next line":          "Variante fertig. N = nächste",
+		"Repertoire move %s is illegal here":    "Repertoirezug %s ist hier unzulässig",
+		"Repertoire drill: %d moves due":        "Repertoire: %d Züge fällig",
+
+		// endgames
+		"K+Q vs K":                              "K+D gegen K",
+		"K+R vs K":                              "K+T gegen K",
+		"Lucena":                                "Lucena",
+		"Philidor":                              "Philidor",
+		"K+P vs K: win":                         "K+B gegen K: Gewinn",
+		"K+P vs K: hold":                        "K+B gegen K: Remis",
+		"Mate within %d moves":                  "Matt in höchstens %d Zügen",
+		"Reach a tablebase win within %d moves": "Tablebase-Gewinn in höchstens %d Zügen erreichen",
+		"Hold the draw for %d moves":            "Remis halten für %d Züge",
+		"Endgame drills off":                    "Endspieltraining aus",
+		"Failed: %s":                            "Nicht bestanden: %s",
+		"Passed: %s":                            "Bestanden: %s",
+		"%s. Shift+N = retry":                   "%s. Shift+N = nochmal",
+		"%s. N = next":                          "%s. N = weiter",
+		"the win slipped away":                  "der Gewinn ist verspielt",
+		"that position is lost":                 "die Stellung ist verloren",
+		"mate in %d":                            "Matt in %d",
+		"no mating material left":               "kein Mattmaterial mehr",
+		"no mate in %d moves":                   "kein Matt in %d Zügen",
+		"promoted in %d moves":                  "umgewandelt in %d Zügen",
+		"tablebase win in %d moves":             "Tablebase-Gewinn in %d Zügen",
+		"the pawn is gone":                      "der Bauer ist weg",
+		"no promotion in %d moves":              "keine Umwandlung in %d Zügen",
+		"the pawn promoted":                     "der Bauer hat umgewandelt",
+		"held for %d moves":                     "%d Züge gehalten",
+		"Endgame %d/%d: %s":                     "Endspiel %d/%d: %s",
+		"Move %d, passed %d":                    "Zug %d, bestanden %d",
+
+		// tooltips
+		"Suggest a move for the side to move":                 "Einen Zug für die Seite am Zug vorschlagen",
+		"Take back the last move":                             "Den letzten Zug zurücknehmen",
+		"Show what the opponent threatens":                    "Zeigen, was der Gegner droht",
+		"Choose your colour before the first move":            "Vor dem ersten Zug die Farbe wählen",
+		"Change the volume; M mutes":                          "Lautstärke ändern; M schaltet stumm",
+		"AI strength":                                         "Spielstärke der KI",
+		"Click a move to review it; scroll for earlier moves": "Zug anklicken, um ihn anzusehen; für frühere Züge scrollen",
+		"Show pieces as figurines (K)":                        "Figuren als Symbole zeigen (K)",
+		"Click to review the position after that move":        "Klicken, um die Stellung nach diesem Zug zu sehen",
+
+		// typed moves
+		"not your turn":    "du bist nicht am Zug",
+		"ambiguous %s: %s": "%s ist mehrdeutig: %s",
+		" or ":             " oder ",
+		"no legal move %s": "kein zulässiger Zug %s",
+
+		// keyboard and screen reader
+		"Switch between Human vs AI and Human vs Human": "Zwischen Mensch gegen KI und Mensch gegen Mensch wechseln",
+		"Hint":                        "Tipp",
+		"Undo":                        "Zurücknehmen",
+		"Show threat":                 "Drohung zeigen",
+		"Play White":                  "Weiß spielen",
+		"Play Black":                  "Schwarz spielen",
+		"Sound volume":                "Lautstärke",
+		"Figurine notation":           "Figurinennotation",
+		"Figurine notation on":        "Figurinennotation an",
+		"Figurine notation off":       "Figurinennotation aus",
+		"Difficulty %s":               "Spielstärke %s",
+		"New game":                    "Neue Partie",
+		"Where are White's pieces":    "Wo stehen die weißen Figuren",
+		"Where are Black's pieces":    "Wo stehen die schwarzen Figuren",
+		"Whose turn":                  "Wer ist am Zug",
+		"%s to move, %s":              "%s am Zug, %s",
+		"Move taken back, %s to move": "Zug zurückgenommen, %s am Zug",
+		"Chess game announcements":    "Ansagen zur Schachpartie",
+		"Chess controls":              "Schachsteuerung",
+		"castles kingside":            "rochiert kurz",
+		"castles queenside":           "rochiert lang",
+		"takes":                       "schlägt",
+		"promotes to %s":              "wandelt um in %s",
+		"%s: no pieces":               "%s: keine Figuren",
+		"%s, empty":                   "%s, leer",
+		"Kings":                       "Könige",
+		"Queens":                      "Damen",
+		"Rooks":                       "Türme",
+		"Bishops":                     "Läufer",
+		"Knights":                     "Springer",
+		"Pawns":                       "Bauern",
+		"queen":                       "Dame",
+		"rook":                        "Turm",
+		"bishop":                      "Läufer",
+		"knight":                      "Springer",
+		"white king":                  "weißer König",
+		"white queen":                 "weiße Dame",
+		"white rook":                  "weißer Turm",
+		"white bishop":                "weißer Läufer",
+		"white knight":                "weißer Springer",
+		"white pawn":                  "weißer Bauer",
+		"black king":                  "schwarzer König",
+		"black queen":                 "schwarze Dame",
+		"black rook":                  "schwarzer Turm",
+		"black bishop":                "schwarzer Läufer",
+		"black knight":                "schwarzer Springer",
+		"black pawn":                  "schwarzer Bauer",
+	},
+	"es": {
+		pieceLetters: "RDTAC",
+
+		// panel
+		"Human vs AI":                          "Humano vs IA",
+		"Human vs Human":                       "Humano vs Humano",
+		"Undo (U)":                             "Deshacer (U)",
+		"Hint (H)":                             "Pista (H)",
+		"Threat (T)":                           "Amenaza (T)",
+		"Color:":                               "Color:",
+		"White":                                "Blancas",
+		"Black":                                "Negras",
+		"(locked)":                             "(bloqueado)",
+		"Difficulty:":                          "Dificultad:",
+		"harmless":                             "inofensivo",
+		"easy":                                 "fácil",
+		"normal":                               "normal",
+		"hard":                                 "difícil",
+		"godlike":                              "divino",
+		"Status: %s":                           "Estado: %s",
+		"Turn: %s":                             "Turno: %s",
+		"Player: %s":                           "Jugador: %s",
+		"Diff: %s":                             "Nivel: %s",
+		"Moves: %d":                            "Jugadas: %d",
+		"Chess960 #%d":                         "Chess960 n.º %d",
+		"Handicap: %s":                         "Ventaja: %s",
+		"%s odds by %s":                        "%s de ventaja de %s",
+		"AI %s/move":                           "IA %s/jugada",
+		"Pieces: %s":                           "Piezas: %s",
+		"Msg: %s":                              "Aviso: %s",
+		"Moves":                                "Jugadas",
+		"Eval: %s cp":                          "Evaluación: %s cp",
+		"Eval graph":                           "Gráfica de evaluación",
+		"Opening: %s":                          "Apertura: %s",
+		"Sound: off":                           "Sonido: no",
+		"Sound: %d%%":                          "Sonido: %d%%",
+		"Move> ":                               "Jugada> ",
+		"in_progress":                          "en juego",
+		"check":                                "jaque",
+		"white_wins":                           "ganan blancas",
+		"black_wins":                           "ganan negras",
+		"draw":                                 "tablas",
+		"stalemate":                            "ahogado",
+		"checkmate":                            "jaque mate",
+		"Normal":                               "Normal",
+		"Discs":                                "Discos",
+		"Opponent hidden":                      "Rival oculto",
+		"Blindfold":                            "A ciegas",
+		"None":                                 "Ninguna",
+		"Pawn":                                 "Peón",
+		"Knight":                               "Caballo",
+		"Bishop":                               "Alfil",
+		"Rook":                                 "Torre",
+		"Queen":                                "Dama",
+		"King":                                 "Rey",
+		"TB: Draw":                             "TB: Tablas",
+		"TB: Win for %s":                       "TB: Ganan %s",
+		"TB: Loss for %s":                      "TB: Pierden %s",
+		"TB: Draw (50-move rule)":              "TB: Tablas (regla de 50)",
+		"TB: Win in %d for %s":                 "TB: Ganan %[2]s en %[1]d",
+		"TB: Loss in %d for %s":                "TB: Pierden %[2]s en %[1]d",
+		"/=type V=pieces Z=peek Tab M=mute":    "/=escribir V=piezas Z=ver Tab M=mudo",
+		"9=960 [ ]=pos C=fen O=odds L=ai time": "9=960 [ ]=pos C=FEN O=ventaja L=t.IA",
+		"H=hint T=threat R=report X=pgn K=♘":   "H=pista T=amenaza R=informe X=PGN K=♘",
+		"Keys: N=new A=mode F=flip E=eval U=undo": "N=nueva A=modo F=girar E=eval U=atrás",
+		"P=puzzles D=drill G=endgames I=coords":   "P=problemas D=repaso G=finales I=coord.",
+
+		// messages
+		"Mode: %s":                              "Modo: %s",
+		"Difficulty: %s":                        "Dificultad: %s",
+		"Back to live position":                 "Vuelta a la posición actual",
+		"Illegal move":                          "Jugada ilegal",
+		"No undo while training":                "No se deshace al entrenar",
+		"No move to undo":                       "No hay jugada que deshacer",
+		"Undid your last move":                  "Se deshizo tu última jugada",
+		"Undid move":                            "Jugada deshecha",
+		"New game (%s)":                         "Nueva partida (%s)",
+		"Viewing ply %d, click board to return": "Viendo media jugada %d, pulsa el tablero para volver",
+		"AI thinking":                           "La IA está pensando",
+		"No moves available":                    "No hay jugadas posibles",
+		"No hint available":                     "No hay pista disponible",
+		"Looking for a hint...":                 "Buscando una pista...",
+		"Hint: %s-%s":                           "Pista: %s-%s",
+		"Threat view on":                        "Amenazas visibles",
+		"Threat view off":                       "Amenazas ocultas",
+		"Coordinates on":                        "Coordenadas visibles",
+		"Coordinates off":                       "Coordenadas ocultas",
+		"Premove %s-%s":                         "Premovimiento %s-%s",
+		"Premove %s-%s is illegal, premoves cancelled": "El premovimiento %s-%s no es legal, premovimientos cancelados",
+		"Premoves cancelled":                           "Premovimientos cancelados",
+		"Pick the promotion piece":                     "Elige la pieza de coronación",
+		"Confirm":                                      "Confirmar",
+		"Confirm moves":                                "Confirmar jugadas",
+		"Confirm moves on":                             "Confirmar jugadas activado",
+		"Confirm moves off":                            "Confirmar jugadas desactivado",
+		"Auto-queen":                                   "Auto-dama",
+		"Auto-queen on":                                "Auto-dama activada",
+		"Auto-queen off":                               "Auto-dama desactivada",
+		"Targets":                                      "Destinos",
+		"Show legal targets":                           "Mostrar destinos legales",
+		"Legal targets shown":                          "Destinos legales visibles",
+		"Legal targets hidden":                         "Destinos legales ocultos",
+		"Click %s again to play, elsewhere to cancel": "Haz clic de nuevo en %s para jugar, en otra casilla para cancelar",
+		"Move cancelled": "Jugada cancelada",
+		"Ask for a second click on the target before a move is played": "Pedir un segundo clic en la casilla de destino antes de jugar",
+		"Promote to a queen without asking":                            "Coronar dama sin preguntar",
+		"Mark the squares the selected piece can move to":              "Marcar las casillas a las que puede ir la pieza elegida",
+		"Export failed":                  "Error al exportar",
+		"Saved %s":                       "Guardado: %s",
+		"Odds: %s":                       "Ventaja: %s",
+		"AI time: %s":                    "Tiempo de la IA: %s",
+		"AI time: %s from the next game": "Tiempo de la IA: %s desde la próxima partida",
+		"Pieces: %s (hold Z to reveal)":  "Piezas: %s (mantén Z para ver)",
+
+		// analysis
+		"No moves to analyse":                "No hay jugadas que analizar",
+		"Analysis complete (R=report X=PGN)": "Análisis listo (R=informe X=PGN)",
+		"Analysis running":                   "Análisis en curso",
+		"Analysing move %d/%d...":            "Analizando jugada %d/%d...",
+		"Game report":                        "Informe de la partida",
+		"Accuracy":                           "Precisión",
+		"Best":                               "Mejor",
+		"Good":                               "Buena",
+		"Inaccuracy":                         "Imprecisión",
+		"Mistake":                            "Error",
+		"Blunder":                            "Error grave",
+		"Key moments:":                       "Momentos clave:",
+		"best %s":                            "mejor %s",
+		"R=close  X=export annotated PGN":    "R=cerrar  X=exportar PGN comentado",
+
+		// puzzles and repertoire
+		"Puzzle mode off":                       "Problemas desactivados",
+		"No puzzles available":                  "No hay problemas disponibles",
+		"Puzzle: find the best move for %s":     "Problema: busca la mejor jugada de %s",
+		"Wrong move (%d), try again":            "Jugada incorrecta (%d), otra vez",
+		"Wrong move, try again":                 "Jugada incorrecta, otra vez",
+		"Solved (not counted). N = next puzzle": "Resuelto (sin puntuar). N = siguiente",
+		"Solved! +%d. N = next puzzle":          "¡Resuelto! +%d. N = siguiente",
+		"Your move":                             "Te toca",
+		"Solved":                                "Resuelto",
+		"Failed, keep trying":                   "Fallado, sigue intentando",
+		"Puzzle %s (%d)":                        "Problema %s (%d)",
+		"Rating %d streak %d best %d":           "Elo %d racha %d mejor %d",
+		"Repertoire drill off":                  "Repaso de repertorio desactivado",
+		"No repertoire loaded":                  "No hay repertorio cargado",
+		"Drill: play your prepared moves as %s": "Repaso: juega tus jugadas con %s",
+		"%s is not in your repertoire: %s":      "%s no está en tu repertorio: %s",
+		"Line complete. N = next line":          "Línea completa. N = siguiente",
+		"Repertoire move %s is illegal here":    "La jugada %s del repertorio es ilegal aquí",
+		"Repertoire drill: %d moves due":        "Repertorio: %d jugadas pendientes",
+
+		// endgames
+		"K+Q vs K":                              "R+D contra R",
+		"K+R vs K":                              "R+T contra R",
+		"Lucena":                                "Lucena",
+		"Philidor":                              "Philidor",
+		"K+P vs K: win":                         "R+P contra R: ganar",
+		"K+P vs K: hold":                        "R+P contra R: tablas",
+		"Mate within %d moves":                  "Mate en %d jugadas o menos",
+		"Reach a tablebase win within %d moves": "Ganar según la tablebase en %d jugadas o menos",
+		"Hold the draw for %d moves":            "Aguantar tablas %d jugadas",
+		"Endgame drills off":                    "Finales desactivados",
+		"Failed: %s":                            "Suspendido: %s",
+		"Passed: %s":                            "Superado: %s",
+		"%s. Shift+N = retry":                   "%s. Shift+N = repetir",
+		"%s. N = next":                          "%s. N = siguiente",
+		"the win slipped away":                  "se escapó la victoria",
+		"that position is lost":                 "esa posición está perdida",
+		"mate in %d":                            "mate en %d",
+		"no mating material left":               "no queda material para mate",
+		"no mate in %d moves":                   "sin mate en %d jugadas",
+		"promoted in %d moves":                  "coronado en %d jugadas",
+		"tablebase win in %d moves":             "victoria de tablebase en %d jugadas",
+		"the pawn is gone":                      "el peón ha caído",
+		"no promotion in %d moves":              "sin coronar en %d jugadas",
+		"the pawn promoted":                     "el peón coronó",
+		"held for %d moves":                     "aguantado %d jugadas",
+		"Endgame %d/%d: %s":                     "Final %d/%d: %s",
+		"Move %d, passed %d":                    "Jugada %d, superados %d",
+
+		// tooltips
+		"Suggest a move for the side to move":                 "Sugerir una jugada para el bando que juega",
+		"Take back the last move":                             "Deshacer la última jugada",
+		"Show what the opponent threatens":                    "Mostrar lo que amenaza el rival",
+		"Choose your colour before the first move":            "Elige tu color antes de la primera jugada",
+		"Change the volume; M mutes":                          "Cambiar el volumen; M silencia",
+		"AI strength":                                         "Fuerza de la IA",
+		"Click a move to review it; scroll for earlier moves": "Haz clic en una jugada para verla; desplaza para ver las anteriores",
+		"Show pieces as figurines (K)":                        "Mostrar las piezas como figuras (K)",
+		"Click to review the position after that move":        "Pulsa para ver la posición tras esa jugada",
+
+		// typed moves
+		"not your turn":    "no es tu turno",
+		"ambiguous %s: %s": "%s es ambigua: %s",
+		" or ":             " o ",
+		"no legal move %s": "ninguna jugada legal %s",
+
+		// keyboard and screen reader
+		"Switch between Human vs AI and Human vs Human": "Cambiar entre humano contra IA y humano contra humano",
+		"Hint":                        "Pista",
+		"Undo":                        "Deshacer",
+		"Show threat":                 "Mostrar amenaza",
+		"Play White":                  "Jugar con blancas",
+		"Play Black":                  "Jugar con negras",
+		"Sound volume":                "Volumen",
+		"Figurine notation":           "Notación con figuras",
+		"Figurine notation on":        "Notación con figuras activada",
+		"Figurine notation off":       "Notación con figuras desactivada",
+		"Difficulty %s":               "Dificultad %s",
+		"New game":                    "Nueva partida",
+		"Where are White's pieces":    "Dónde están las piezas blancas",
+		"Where are Black's pieces":    "Dónde están las piezas negras",
+		"Whose turn":                  "A quién le toca",
+		"%s to move, %s":              "Juegan %s, %s",
+		"Move taken back, %s to move": "Jugada deshecha, juegan %s",
+		"Chess game announcements":    "Avisos de la partida",
+		"Chess controls":              "Controles de ajedrez",
+		"castles kingside":            "enroque corto",
+		"castles queenside":           "enroque largo",
+		"takes":                       "captura",
+		"promotes to %s":              "corona %s",
+		"%s: no pieces":               "%s: sin piezas",
+		"%s, empty":                   "%s, vacía",
+		"Kings":                       "Reyes",
+		"Queens":                      "Damas",
+		"Rooks":                       "Torres",
+		"Bishops":                     "Alfiles",
+		"Knights":                     "Caballos",
+		"Pawns":                       "Peones",
+		"queen":                       "dama",
+		"rook":                        "torre",
+		"bishop":                      "alfil",
+		"knight":                      "caballo",
+		"white king":                  "rey blanco",
+		"white queen":                 "dama blanca",
+		"white rook":                  "torre blanca",
+		"white bishop":                "alfil blanco",
+		"white knight":                "caballo blanco",
+		"white pawn":                  "peón blanco",
+		"black king":                  "rey negro",
+		"black queen":                 "dama negra",
+		"black rook":                  "torre negra",
+		"black bishop":                "alfil negro",
+		"black knight":                "caballo negro",
+		"black pawn":                  "peón negro",
+	},
+}
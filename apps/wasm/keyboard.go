@@ -0,0 +1,146 @@
+package main
+
+import (
+	"image/color"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"go.rumenx.com/chess/engine"
+)
+
+// panelControls lists the panel controls in Tab order.
+func (u *uiGame) panelControls() []panelControl { return u.panel.controls() }
+
+// handleBoardKeys drives the keyboard cursor: arrows move it over the board,
+// Enter acts like a click on the cursor square, Tab jumps between the legal
+// targets of the selected piece (Shift+Tab backwards) and, with nothing
+// selected, between the panel controls. Page Up/Down step through the game
+// like the evaluation graph.
+func (u *uiGame) handleBoardKeys() {
+	back := ebiten.IsKeyPressed(ebiten.KeyShift)
+	moves := []struct {
+		key    ebiten.Key
+		df, dr int
+	}{{ebiten.KeyArrowLeft, -1, 0}, {ebiten.KeyArrowRight, 1, 0}, {ebiten.KeyArrowUp, 0, 1}, {ebiten.KeyArrowDown, 0, -1}}
+	for _, m := range moves {
+		if !inpututil.IsKeyJustPressed(m.key) {
+			continue
+		}
+		u.panelFocus = -1
+		if u.cursor == nil {
+			u.cursor = u.cursorStart()
+			continue
+		}
+		dr := m.dr
+		if !u.whiteAtBottom {
+			dr = -dr // the board is drawn with rank 1 at the top
+		}
+		f, r := u.cursor.File()+m.df, u.cursor.Rank()+dr
+		if f >= 0 && f < 8 && r >= 0 && r < 8 {
+			sq := engine.Square(r*8 + f)
+			u.cursor = &sq
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		if u.selected != nil && len(u.legalTargets) > 0 {
+			u.cycleTarget(back)
+		} else {
+			n := len(u.panelControls())
+			if n == 0 {
+				u.panelFocus = -1
+			} else if back && u.panelFocus <= 0 {
+				u.panelFocus = n - 1
+			} else if back {
+				u.panelFocus--
+			} else {
+				u.panelFocus = (u.panelFocus + 1) % n
+			}
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) {
+		switch {
+		case u.panelFocus >= 0:
+			// the panel's lists can shrink while a control is focused
+			if controls := u.panelControls(); u.panelFocus < len(controls) {
+				controls[u.panelFocus].activate()
+			} else {
+				u.panelFocus = len(controls) - 1
+			}
+		case u.cursor != nil:
+			u.clickSquare(*u.cursor)
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyPageUp) {
+		ply := u.ply()
+		if u.viewing() {
+			ply = u.viewPly
+		}
+		if ply > 0 {
+			u.setViewPly(ply - 1)
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyPageDown) && u.viewing() {
+		u.setViewPly(u.viewPly + 1)
+	}
+}
+
+// cursorStart puts a new cursor on the e-file in front of the player's king.
+func (u *uiGame) cursorStart() *engine.Square {
+	sq := engine.Square(1*8 + 4)
+	if u.playerColor == engine.Black {
+		sq = engine.Square(6*8 + 4)
+	}
+	return &sq
+}
+
+// cycleTarget moves the cursor to the next (or previous) legal target of
+// the selected piece, in board order.
+func (u *uiGame) cycleTarget(back bool) {
+	var targets []engine.Square
+	for sq := range u.legalTargets {
+		targets = append(targets, sq)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i] < targets[j] })
+	idx := -1
+	for i, sq := range targets {
+		if u.cursor != nil && sq == *u.cursor {
+			idx = i
+		}
+	}
+	switch {
+	case back && idx <= 0:
+		idx = len(targets) - 1
+	case back:
+		idx--
+	default:
+		idx = (idx + 1) % len(targets)
+	}
+	sq := targets[idx]
+	u.cursor = &sq
+}
+
+// keyboardFocusColor outlines the keyboard cursor and the focused panel control.
+var keyboardFocusColor = color.RGBA{0xFF, 0xC0, 0x20, 0xFF}
+
+// drawCursor outlines the keyboard cursor square on the board image.
+func (u *uiGame) drawCursor(board *ebiten.Image) {
+	if u.cursor == nil || u.panelFocus >= 0 {
+		return
+	}
+	vrank := u.cursor.Rank()
+	if u.whiteAtBottom {
+		vrank = 7 - vrank
+	}
+	x, y := float32(u.cursor.File()*squareSize), float32(vrank*squareSize)
+	vector.StrokeRect(board, x+2, y+2, squareSize-4, squareSize-4, 3, keyboardFocusColor, false)
+}
+
+// drawKeyboardFocus outlines the focused panel control.
+func (u *uiGame) drawKeyboardFocus(screen *ebiten.Image) {
+	if controls := u.panelControls(); u.panelFocus >= 0 && u.panelFocus < len(controls) {
+		r := controls[u.panelFocus].rect
+		vector.StrokeRect(screen, float32(r.Min.X-2), float32(r.Min.Y-2), float32(r.Dx()+4), float32(r.Dy()+4), 2, keyboardFocusColor, false)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !(js && wasm)
+
+package main
+
+import "os"
+
+// userLocales returns the locales named by CHESS_LANG and the usual POSIX
+// variables, most important first.
+func userLocales() []string {
+	var locales []string
+	for _, name := range []string{"CHESS_LANG", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			locales = append(locales, v)
+		}
+	}
+	return locales
+}
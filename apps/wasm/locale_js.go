@@ -0,0 +1,24 @@
+//go:build js && wasm
+
+package main
+
+import "syscall/js"
+
+// userLocales returns the "lang" query parameter of the page URL followed by
+// the browser's preferred languages.
+func userLocales() []string {
+	var locales []string
+	params := js.Global().Get("URLSearchParams").New(js.Global().Get("location").Get("search"))
+	if lang := params.Call("get", "lang"); lang.Truthy() {
+		locales = append(locales, lang.String())
+	}
+	nav := js.Global().Get("navigator")
+	if langs := nav.Get("languages"); langs.Truthy() {
+		for i := 0; i < langs.Length(); i++ {
+			locales = append(locales, langs.Index(i).String())
+		}
+	} else if lang := nav.Get("language"); lang.Truthy() {
+		locales = append(locales, lang.String())
+	}
+	return locales
+}
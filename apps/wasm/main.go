@@ -13,6 +13,7 @@ import (
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"go.rumenx.com/chess/ai"
 	"go.rumenx.com/chess/engine"
 )
@@ -56,6 +57,31 @@ type uiGame struct {
 	aiEngine     ai.Engine
 	aiPending    bool
 	aiCtxCancel  context.CancelFunc
+	book         *openingBook // consulted before the AI searches (see book.go)
+	tb           *tablebase   // Syzygy tables, nil when not configured (see tablebase.go)
+	tbText       string
+	tbPly        int
+	tbPending    bool
+	tbWDL        int // WDL for the side to move, valid when tbWDLPly is the current ply
+	tbWDLPly     int
+	startFEN     string        // X-FEN of the start position, empty for the standard one
+	before       []engine.Move // moves up to the last Chess960 castle, where g starts (see chess960.go)
+	beforeSAN    []string
+	chess960     bool
+	chess960Pos  int
+	odds         oddsKind        // material the AI side gives up (see odds.go)
+	aiMoveTime   time.Duration   // AI thinking time per move
+	nextAITime   time.Duration   // the time selected for the next game
+	puzzle       *puzzleSession  // non-nil in puzzle mode (see puzzle.go)
+	drill        *drillSession   // non-nil in repertoire drill mode (see repertoire.go)
+	endgame      *endgameSession // non-nil in endgame drill mode (see endgame.go)
+	blind        blindMode       // hidden-piece training display (see blindfold.go)
+	typing       bool            // the typed move box has the keyboard (see moveinput.go)
+	typed        string
+	typedErr     string
+	panel        *panel         // side panel widgets (see panel.go)
+	cursor       *engine.Square // keyboard cursor, nil until an arrow key is used (see keyboard.go)
+	panelFocus   int            // panel control focused with Tab, -1 for none
 	selected     *engine.Square
 	legalTargets map[engine.Square]bool
 	legalMoves   map[engine.Square]engine.Move
@@ -81,39 +107,116 @@ type uiGame struct {
 	rasterTool   string // selected external tool (rsvg-convert or inkscape)
 	rasterWarned bool   // logged missing tool once
 	wasMouseDown bool   // for edge-trigger mouse click detection
+	// hint and threat arrows (see hint.go)
+	hintMove      *engine.Move
+	hintPly       int
+	hintPending   bool
+	showThreat    bool
+	threatMove    *engine.Move
+	threatPly     int
+	threatPending bool
+	// evaluation bar / graph (see eval.go)
+	evalHist    []evalResult // score per ply, index 0 is the start position
+	evalPending bool
+	evalGen     int
+	// past position shown from the graph (see view.go)
+	viewPly  int
+	viewGame *engine.Game
+	// post-game analysis (see analysis.go)
+	report           *gameReport
+	reportPending    bool
+	reportProgress   int
+	reportCheckedPly int
+	showReport       bool
+	// ECO classification of the live game (see eco.go)
+	opening    *ecoOpening
+	openingPly int
+	// screen reader bridge (see a11y.go)
+	a11yQueue  chan func() // actions from the accessibility buttons, run by Update
+	a11yPly    int
+	a11yMsg    string
+	a11yCursor engine.Square
+	// audio cues (see sound.go)
+	sound        *soundPlayer // nil until audio has started
+	volume       int          // percent, 0 when muted
+	unmuteVolume int
+	soundPly     int
+	figurines    bool // figurine piece symbols in the move list (see movelist.go)
+	// board drawing and captured pieces (see captured.go)
+	boardImg *ebiten.Image // the board is drawn here and then placed at boardTop
+	captured capturedCache
+	// coordinates and annotations (see coords.go and annotate.go)
+	showCoords bool
+	marks      map[int][]boardMark // arrows and circles by the ply of their position
+	markFrom   *engine.Square      // where a right-button drag started
+	// moves queued during the AI's turn (see premove.go)
+	premoves    []premove
+	premoveFrom *engine.Square
+	// move input options (see options.go)
+	confirmMoves bool
+	autoQueen    bool
+	showTargets  bool
+	promoting    *engine.Move // waiting for the promotion piece
+	confirming   *engine.Move // waiting for the second click
+	// touch screen input (see touch.go)
+	touch      *touchGesture
+	touchQuiet int // ticks left in which the mouse is ignored
 }
 
 const (
 	boardPixels = 640
 	squareSize  = boardPixels / 8
 	panelWidth  = 240
-	windowW     = boardPixels + panelWidth
-	windowH     = boardPixels
+	panelX      = boardPixels + evalBarWidth
+	windowW     = panelX + panelWidth
+	boardTop    = captureBarHeight // the board sits between the captured piece bars
+	windowH     = boardPixels + 2*captureBarHeight
 )
 
 func newUIGame() *uiGame {
 	g := engine.NewGame()
 	ug := &uiGame{
-		g:             g,
-		mode:          HumanVsAI,
-		aiEngine:      ai.NewMinimaxAI(ai.DifficultyMedium),
-		difficulty:    ai.DifficultyMedium,
-		legalTargets:  map[engine.Square]bool{},
-		legalMoves:    map[engine.Square]engine.Move{},
-		playerColor:   engine.White,
-		whiteAtBottom: true,
-		pieceCache:    map[string]*ebiten.Image{},
-		imageBaseDir:  "examples/gui/assets/pieces",
+		g:                g,
+		mode:             HumanVsAI,
+		aiEngine:         ai.NewMinimaxAI(ai.DifficultyMedium),
+		book:             loadOpeningBook(),
+		tb:               loadTablebase(),
+		difficulty:       ai.DifficultyMedium,
+		legalTargets:     map[engine.Square]bool{},
+		legalMoves:       map[engine.Square]engine.Move{},
+		playerColor:      engine.White,
+		whiteAtBottom:    true,
+		pieceCache:       map[string]*ebiten.Image{},
+		imageBaseDir:     "examples/gui/assets/pieces",
+		threatPly:        -1,
+		viewPly:          -1,
+		reportCheckedPly: -1,
+		tbPly:            -1,
+		tbWDLPly:         -1,
+		panelFocus:       -1,
+		aiMoveTime:       aiMoveTimes[0],
+		nextAITime:       aiMoveTimes[0],
+		volume:           loadVolume(),
+		figurines:        loadFlag(figurineSettingKey, false),
+		boardImg:         ebiten.NewImage(boardPixels, boardPixels),
+		showCoords:       loadFlag(coordinatesSettingKey, true),
+		confirmMoves:     loadFlag(confirmMovesSettingKey, false),
+		autoQueen:        loadFlag(autoQueenSettingKey, true),
+		showTargets:      loadFlag(showTargetsSettingKey, true),
 	}
 	ug.detectRasterTool()
+	ug.loadStartupFEN()
+	ug.panel = ug.newPanel()
+	ug.a11yQueue = make(chan func(), 16)
+	setupAccessibility(ug.a11yActions(), ug.a11yQueue)
 	return ug
 }
 
 func (u *uiGame) Layout(outsideWidth, outsideHeight int) (int, int) { return windowW, windowH }
 
 func (u *uiGame) Update() error {
-	// Handle quit keys
-	if ebiten.IsKeyPressed(ebiten.KeyEscape) || ebiten.IsKeyPressed(ebiten.KeyQ) {
+	// Handle quit keys (not while typing a move, where Esc closes the box)
+	if !u.typing && (inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyQ)) {
 		return ebiten.Termination
 	}
 
@@ -124,23 +227,66 @@ func (u *uiGame) Update() error {
 
 	// Input handling
 	u.cursorX, u.cursorY = ebiten.CursorPosition()
-	u.handleKeys()
+	u.panel.update(u.cursorX, u.cursorY)
+	if !u.handleTyping() {
+		u.handleKeys()
+	}
+	u.handleTouch()
 	u.handleMouse()
+	u.handleAnnotations()
+	u.trimMarks()
 
 	// If AI move pending, poll (goroutine will set lastMove when done)
-	if u.mode == HumanVsAI && !u.aiPending {
+	u.playPremove()
+	if u.training() {
+		u.updatePuzzle()
+		u.updateDrill()
+	} else if (u.mode == HumanVsAI || u.endgame != nil) && !u.aiPending {
 		if u.g.ActiveColor() == u.aiColor() {
 			u.startAIMove()
 		}
 	}
+	u.updateEndgame()
+	u.updateThreat()
+	u.updateEvalHistory()
+	u.updateReport()
+	u.updateOpening()
+	u.updateTablebase()
+	u.updateSound()
+	u.updateCaptured()
+	u.updateAnnouncements()
 
 	return nil
 }
 
-func (u *uiGame) handleKeys() {
-	if ebiten.IsKeyPressed(ebiten.KeyN) {
+// newGame moves on like the N key: to the next puzzle or drill line when
+// training, to the next endgame drill (the same one again with retry), to a
+// new Chess960 position or to a fresh game.
+func (u *uiGame) newGame(retry bool) {
+	switch {
+	case u.training():
+		if u.puzzle != nil {
+			u.nextPuzzle()
+		} else {
+			u.nextDrillLine()
+		}
+	case u.endgame != nil:
+		next := u.endgame.idx + 1
+		if retry {
+			next--
+		}
+		u.startEndgame(next)
+	case u.chess960:
+		u.startChess960(-1)
+	default:
 		u.resetGame(u.playerColor)
 	}
+}
+
+func (u *uiGame) handleKeys() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyN) {
+		u.newGame(ebiten.IsKeyPressed(ebiten.KeyShift))
+	}
 	if ebiten.IsKeyPressed(ebiten.KeyU) { // undo last move
 		u.handleUndo()
 	}
@@ -148,13 +294,7 @@ func (u *uiGame) handleKeys() {
 		u.cycleDifficulty()
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyA) {
-		if u.mode == HumanVsAI {
-			u.mode = HumanVsHuman
-		} else {
-			u.mode = HumanVsAI
-		}
-		u.flashMsg("Mode: " + u.modeString())
-		u.selected = nil
+		u.toggleMode()
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyE) {
 		s := u.g.Evaluate()
@@ -163,23 +303,111 @@ func (u *uiGame) handleKeys() {
 	if ebiten.IsKeyPressed(ebiten.KeyF) {
 		u.whiteAtBottom = !u.whiteAtBottom
 	}
-	// style toggle removed (always attempt images)
-	if len(u.g.MoveHistory()) == 0 { // allow choosing color before first move only
-		if ebiten.IsKeyPressed(ebiten.KeyW) && u.playerColor != engine.White {
-			u.playerColor = engine.White
-			u.whiteAtBottom = true
-			u.resetGame(u.playerColor)
+	if inpututil.IsKeyJustPressed(ebiten.KeyH) {
+		u.requestHint()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyT) {
+		u.toggleThreat()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyR) {
+		u.toggleReport()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyX) {
+		u.exportPGN()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyC) {
+		u.exportFEN(ebiten.IsKeyPressed(ebiten.KeyShift))
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyP) {
+		u.togglePuzzles()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyD) {
+		u.toggleDrill()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyM) {
+		u.toggleMute()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyK) {
+		u.toggleFigurines()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyI) {
+		u.toggleCoordinates()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyV) {
+		u.cycleBlind()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyG) {
+		u.toggleEndgames()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyO) {
+		u.cycleOdds()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyL) {
+		u.cycleAITime()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.Key9) {
+		u.toggleChess960()
+	}
+	if u.chess960 {
+		step := 1
+		if ebiten.IsKeyPressed(ebiten.KeyShift) {
+			step = 100
 		}
-		if ebiten.IsKeyPressed(ebiten.KeyB) && u.playerColor != engine.Black {
-			u.playerColor = engine.Black
-			u.whiteAtBottom = false
-			u.resetGame(u.playerColor)
+		if inpututil.IsKeyJustPressed(ebiten.KeyBracketLeft) {
+			u.startChess960(u.chess960Pos - step)
 		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyBracketRight) {
+			u.startChess960(u.chess960Pos + step)
+		}
+	}
+	// style toggle removed (always attempt images)
+	if ebiten.IsKeyPressed(ebiten.KeyW) {
+		u.chooseColor(engine.White)
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyB) {
+		u.chooseColor(engine.Black)
+	}
+	u.handleBoardKeys()
+}
+
+// toggleMode switches between Human vs AI and Human vs Human.
+func (u *uiGame) toggleMode() {
+	if u.mode == HumanVsAI {
+		u.mode = HumanVsHuman
+	} else {
+		u.mode = HumanVsAI
 	}
+	u.flashMsg(trf("Mode: %s", u.modeString()))
+	u.selected = nil
+	u.premoves, u.premoveFrom = nil, nil
+}
+
+// chooseColor starts a new game as c; allowed before the first move only.
+func (u *uiGame) chooseColor(c engine.Color) {
+	if u.ply() > 0 || u.training() || u.endgame != nil || u.playerColor == c {
+		return
+	}
+	u.playerColor = c
+	u.whiteAtBottom = c == engine.White
+	u.resetGame(u.playerColor)
+}
+
+func (u *uiGame) setDifficulty(d ai.Difficulty) {
+	if u.difficulty == d {
+		return
+	}
+	u.difficulty = d
+	u.aiEngine = ai.NewMinimaxAI(u.difficulty)
+	u.flashMsg(trf("Difficulty: %s", tr(u.difficultyLabel())))
 }
 
 func (u *uiGame) handleMouse() {
 	pressed := ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+	if u.touchQuiet > 0 {
+		// touches are read by handleTouch; ignore the mouse events a browser derives from them
+		u.wasMouseDown = pressed
+		return
+	}
 	if !pressed {
 		u.wasMouseDown = false
 		return
@@ -190,60 +418,22 @@ func (u *uiGame) handleMouse() {
 	}
 	u.wasMouseDown = true
 	x, y := ebiten.CursorPosition()
-	// Panel clicks
-	if x >= boardPixels {
-		relX := x - boardPixels
-		relY := y
-		// Mode toggle button at y 8-28
-		if relY >= 8 && relY < 28 && relX >= 8 && relX < 8+120 {
-			if u.mode == HumanVsAI {
-				u.mode = HumanVsHuman
-			} else {
-				u.mode = HumanVsAI
-			}
-			u.flashMsg("Mode: " + u.modeString())
-			return
-		}
-		// Undo button (y 32-52)
-		if relY >= 32 && relY < 52 && relX >= 8 && relX < 8+120 {
-			u.handleUndo()
-			return
-		}
-		// color selection boxes (moved up after removing style button)
-		if relY >= 56 && relY < 76 && len(u.g.MoveHistory()) == 0 && relX >= 8 && relX < 8+90 {
-			if u.playerColor != engine.White {
-				u.playerColor = engine.White
-				u.whiteAtBottom = true
-				u.resetGame(u.playerColor)
-			}
-			return
-		} else if relY >= 81 && relY < 101 && len(u.g.MoveHistory()) == 0 && relX >= 8 && relX < 8+90 {
-			if u.playerColor != engine.Black {
-				u.playerColor = engine.Black
-				u.whiteAtBottom = false
-				u.resetGame(u.playerColor)
-			}
-			return
-		}
-		// difficulty list starting at new y 135, each 22px height
-		if relY >= 135 && relY < 135+5*22 && relX >= 8 && relX < 8+120 {
-			idx := (relY - 135) / 22
-			order := []ai.Difficulty{ai.DifficultyBeginner, ai.DifficultyEasy, ai.DifficultyMedium, ai.DifficultyHard, ai.DifficultyExpert}
-			if idx >= 0 && idx < len(order) {
-				if u.difficulty != order[idx] {
-					u.difficulty = order[idx]
-					u.aiEngine = ai.NewMinimaxAI(u.difficulty)
-					u.flashMsg("Difficulty: " + u.difficultyLabel())
-				}
-			}
-			return
-		}
-		_ = relX // reserved for possible future use
+	// Panel clicks go to the widget under the pointer (see widget.go)
+	if x >= panelX {
+		u.panel.click(x, y)
 		return
 	}
 	// Board clicks
+	if sq, ok := u.squareAt(x, y); ok {
+		u.clickSquare(sq)
+	}
+}
+
+// squareAt returns the board square under the screen point (x, y).
+func (u *uiGame) squareAt(x, y int) (engine.Square, bool) {
+	y -= boardTop
 	if x < 0 || x >= boardPixels || y < 0 || y >= boardPixels {
-		return
+		return 0, false
 	}
 	file := x / squareSize
 	var rank int
@@ -252,8 +442,32 @@ func (u *uiGame) handleMouse() {
 	} else {
 		rank = y / squareSize
 	}
-	sq := engine.Square(rank*8 + file)
+	return engine.Square(rank*8 + file), true
+}
 
+// clickSquare selects a piece or plays the selected piece to sq, for mouse
+// clicks and the keyboard cursor alike.
+func (u *uiGame) clickSquare(sq engine.Square) {
+	if u.showReport {
+		u.showReport = false
+		return
+	}
+	if u.viewing() {
+		u.leaveView()
+		u.flashMsg(tr("Back to live position"))
+		return
+	}
+	switch {
+	case u.promoting != nil:
+		u.clickPromotion(sq)
+		return
+	case u.confirming != nil:
+		u.clickConfirm(sq)
+		return
+	case u.aiToMove():
+		u.clickPremove(sq)
+		return
+	}
 	if u.selected == nil {
 		p := u.g.Board().GetPiece(sq)
 		if p.IsEmpty() || p.Color != u.g.ActiveColor() {
@@ -271,23 +485,13 @@ func (u *uiGame) handleMouse() {
 	if u.legalTargets[sq] { // perform move
 		// Prefer using precomputed legal move (handles promotions, castling etc.)
 		if mv, ok := u.legalMoves[sq]; ok {
-			// Auto-queen promotion if needed (simplified)
-			if mv.Piece.Type == engine.Pawn && (sq.Rank() == 7 || sq.Rank() == 0) && mv.Type != engine.Promotion {
-				mv.Type = engine.Promotion
-				mv.Promotion = engine.Queen
-			}
-			u.applyMove(mv)
+			u.chooseMove(mv)
 		} else {
 			// Fallback parse
 			notation := u.selected.String() + sq.String()
 			mv, err := u.g.ParseMove(notation)
 			if err == nil && u.g.IsLegalMove(mv) {
-				// auto queen if reaches end rank
-				if mv.Piece.Type == engine.Pawn && (sq.Rank() == 7 || sq.Rank() == 0) && mv.Type != engine.Promotion {
-					mv.Type = engine.Promotion
-					mv.Promotion = engine.Queen
-				}
-				u.applyMove(mv)
+				u.chooseMove(mv)
 			}
 		}
 	}
@@ -296,20 +500,40 @@ func (u *uiGame) handleMouse() {
 }
 
 func (u *uiGame) Draw(screen *ebiten.Image) {
-	u.drawBoard(screen)
-	u.drawHighlights(screen)
-	u.drawPieces(screen)
+	// everything on the board is drawn in board coordinates
+	board := u.boardImg
+	u.drawBoard(board)
+	u.drawCoordinates(board)
+	u.drawHighlights(board)
+	u.drawPremoves(board)
+	u.drawPieces(board)
+	u.drawPendingMove(board)
+	if !u.viewing() {
+		u.drawHints(board)
+	}
+	u.drawMarks(board)
+	u.drawReport(board)
+	u.drawCursor(board)
+	screen.DrawImage(board, &ebiten.DrawImageOptions{GeoM: translate(0, boardTop)})
+	u.drawCaptured(screen)
+	u.drawEvalBar(screen)
 	u.drawPanel(screen)
+	u.drawKeyboardFocus(screen)
+	u.drawTouchDrag(screen)
 }
 
+// Board square colours.
+var (
+	lightSquare = color.RGBA{0xEE, 0xD9, 0xB6, 0xFF}
+	darkSquare  = color.RGBA{0xB5, 0x88, 0x63, 0xFF}
+)
+
 func (u *uiGame) drawBoard(screen *ebiten.Image) {
-	light := color.RGBA{0xEE, 0xD9, 0xB6, 0xFF}
-	dark := color.RGBA{0xB5, 0x88, 0x63, 0xFF}
 	for vrank := 0; vrank < 8; vrank++ { // visual rank top->bottom
 		for file := 0; file < 8; file++ {
-			c := light
+			c := lightSquare
 			if (vrank+file)%2 == 1 {
-				c = dark
+				c = darkSquare
 			}
 			rect := ebiten.NewImage(squareSize, squareSize)
 			rect.Fill(c)
@@ -320,7 +544,7 @@ func (u *uiGame) drawBoard(screen *ebiten.Image) {
 }
 
 func (u *uiGame) drawPieces(screen *ebiten.Image) {
-	board := u.g.Board()
+	board := u.displayGame().Board()
 	for sq := engine.Square(0); sq < 64; sq++ {
 		p := board.GetPiece(sq)
 		if p.IsEmpty() {
@@ -334,20 +558,25 @@ func (u *uiGame) drawPieces(screen *ebiten.Image) {
 		}
 		x := vx * squareSize
 		y := vy * squareSize
+		if u.drawBlindPiece(screen, p, x, y) {
+			continue
+		}
 		img := u.pieceImage(p)
 		screen.DrawImage(img, &ebiten.DrawImageOptions{GeoM: translate(x, y)})
 	}
 }
 
 func (u *uiGame) drawHighlights(screen *ebiten.Image) {
-	if u.lastMove != nil {
-		u.highlightSquare(screen, u.lastMove.From, color.RGBA{0x66, 0xFF, 0x66, 0x55})
-		u.highlightSquare(screen, u.lastMove.To, color.RGBA{0x66, 0xFF, 0x66, 0x55})
+	if last := u.displayLastMove(); last != nil {
+		u.highlightSquare(screen, last.From, color.RGBA{0x66, 0xFF, 0x66, 0x55})
+		u.highlightSquare(screen, last.To, color.RGBA{0x66, 0xFF, 0x66, 0x55})
 	}
 	if u.selected != nil {
 		u.highlightSquare(screen, *u.selected, color.RGBA{0x33, 0x66, 0xFF, 0x66})
-		for tgt := range u.legalTargets {
-			u.highlightCircle(screen, tgt, color.RGBA{0x33, 0x66, 0xFF, 0xAA})
+		if u.showTargets {
+			for tgt := range u.legalTargets {
+				u.highlightCircle(screen, tgt, color.RGBA{0x33, 0x66, 0xFF, 0xAA})
+			}
 		}
 	}
 }
@@ -381,63 +610,19 @@ func (u *uiGame) highlightCircle(screen *ebiten.Image, sq engine.Square, c color
 }
 
 func (u *uiGame) drawPanel(screen *ebiten.Image) {
-	x0 := boardPixels
 	panel := ebiten.NewImage(panelWidth, windowH)
 	panel.Fill(color.RGBA{0x22, 0x22, 0x22, 0xFF})
-	screen.DrawImage(panel, &ebiten.DrawImageOptions{GeoM: translate(x0, 0)})
-
-	// status strings now built later in infoLines
-	var eval string
-	if u.evalScore != nil {
-		eval = evalString(*u.evalScore)
-	}
-	// Hover detection helper
-	hover := func(x, y, w, h int) bool {
-		return u.cursorX >= boardPixels+x && u.cursorX < boardPixels+x+w && u.cursorY >= y && u.cursorY < y+h
-	}
-	// Mode toggle
-	drawSelectableBox(screen, x0+8, 8, 120, 20, u.modeString(), hover(8, 8, 120, 20))
-	drawSelectableBox(screen, x0+8, 32, 120, 20, "Undo (U)", hover(8, 32, 120, 20))
-	ebitenutil.DebugPrintAt(screen, "Color:", x0+8, 58)
-	drawSelectableBox(screen, x0+8, 56, 90, 20, "White", (u.playerColor == engine.White && len(u.g.MoveHistory()) == 0) || hover(8, 56, 90, 20))
-	drawSelectableBox(screen, x0+8, 81, 90, 20, "Black", (u.playerColor == engine.Black && len(u.g.MoveHistory()) == 0) || hover(8, 81, 90, 20))
-	if len(u.g.MoveHistory()) > 0 {
-		ebitenutil.DebugPrintAt(screen, "(locked)", x0+104, 64)
-	}
-	// Difficulty list
-	ebitenutil.DebugPrintAt(screen, "Difficulty:", x0+8, 115)
-	diffs := []ai.Difficulty{ai.DifficultyBeginner, ai.DifficultyEasy, ai.DifficultyMedium, ai.DifficultyHard, ai.DifficultyExpert}
-	for i, d := range diffs {
-		sel := d == u.difficulty
-		label := u.difficultyCustomLabel(d)
-		drawSelectableBox(screen, x0+8, 135+i*22, 120, 20, label, sel || hover(8, 135+i*22, 120, 20))
-	}
-	// Info section starting below selectors
-	infoY := 135 + len(diffs)*22 + 16
-	infoLines := []string{"Status: " + u.g.Status().String(), "Turn: " + u.g.ActiveColor().String(), "Player: " + u.playerColor.String(), "Diff: " + u.difficultyLabel(), "Moves: " + stringFromInt(len(u.g.MoveHistory()))}
-	if u.evalScore != nil {
-		infoLines = append(infoLines, eval)
-	}
-	if u.msg != "" {
-		infoLines = append(infoLines, "Msg: "+u.msg)
-	}
-	for i, l := range infoLines {
-		ebitenutil.DebugPrintAt(screen, l, x0+8, infoY+i*14)
-	}
-	// SAN list (last 14 entries)
-	sanStartY := infoY + len(infoLines)*14 + 12
-	ebitenutil.DebugPrintAt(screen, "SAN (latest):", x0+8, sanStartY)
-	maxShow := 14
-	san := u.movesSAN
-	if len(san) > maxShow {
-		san = san[len(san)-maxShow:]
-	}
-	for i, mv := range san {
-		ebitenutil.DebugPrintAt(screen, mv, x0+8, sanStartY+14*(i+1))
-	}
-	// Help at bottom
-	ebitenutil.DebugPrintAt(screen, "Keys: N=new A=mode F=flip E=eval U=undo", x0+8, windowH-40)
-	ebitenutil.DebugPrintAt(screen, "Click to select pieces & buttons", x0+8, windowH-24)
+	screen.DrawImage(panel, &ebiten.DrawImageOptions{GeoM: translate(panelX, 0)})
+	u.panel.draw(screen)
+}
+
+// autoQueen turns a pawn move to the last rank into a queen promotion.
+func autoQueen(mv engine.Move) engine.Move {
+	if isPromotion(mv) {
+		mv.Type = engine.Promotion
+		mv.Promotion = engine.Queen
+	}
+	return mv
 }
 
 func (u *uiGame) computeLegalTargets() {
@@ -446,31 +631,50 @@ func (u *uiGame) computeLegalTargets() {
 	if u.selected == nil {
 		return
 	}
-	all := u.g.GetAllLegalMoves()
+	all := u.allLegalMoves()
 	for _, mv := range all {
 		if mv.From == *u.selected {
 			u.legalTargets[mv.To] = true
 			u.legalMoves[mv.To] = mv
+			if mv.Type == engine.Castling {
+				// castling may also be entered by moving the king onto its rook
+				if rook, ok := castlingRookSquare(u.g, mv); ok {
+					u.legalTargets[rook] = true
+					u.legalMoves[rook] = mv
+				}
+			}
 		}
 	}
 }
 
 func (u *uiGame) applyMove(mv engine.Move) {
-	if err := u.g.MakeMove(mv); err != nil {
-		u.flashMsg("Illegal move")
+	if err := u.makeMove(mv); err != nil {
+		u.flashMsg(tr("Illegal move"))
+		u.playSound(soundIllegal)
 		return
 	}
 	u.lastMove = &mv
-	u.movesSAN = u.g.GenerateSAN()
+	u.movesSAN = u.gameSAN()
 	u.evalScore = nil
 	u.lastUndone = false
+	u.clearHints()
+	if u.puzzle != nil {
+		u.puzzleMoved(mv)
+	}
+	if u.drill != nil {
+		u.drillMoved()
+	}
 }
 
 // handleUndo attempts to undo the last move (single ply) if available.
 // It prevents undoing while an AI move is pending.
 func (u *uiGame) handleUndo() {
 	if u.aiPending {
-		u.flashMsg("AI thinking")
+		u.flashMsg(tr("AI thinking"))
+		return
+	}
+	if u.training() || u.endgame != nil {
+		u.flashMsg(tr("No undo while training"))
 		return
 	}
 	// In HumanVsAI mode, if it's player's turn then last move was AI's; undo twice to revert player's last move.
@@ -480,43 +684,67 @@ func (u *uiGame) handleUndo() {
 	}
 	undone := 0
 	for i := 0; i < undoCount; i++ {
-		mv, err := u.g.UndoMove()
-		if err != nil {
+		if err := u.undoMove(); err != nil {
 			if undone == 0 {
-				u.flashMsg("No move to undo")
+				u.flashMsg(tr("No move to undo"))
 			}
 			break
 		}
 		undone++
-		_ = mv
 	}
 	if undone > 0 {
-		u.movesSAN = u.g.GenerateSAN()
+		u.movesSAN = u.gameSAN()
 		u.lastMove = nil
 		u.evalScore = nil
 		u.lastUndone = true
+		u.clearHints()
+		u.leaveView()
+		u.dropPendingMove()
+		u.resetEvalHistory(u.ply() + 1)
+		u.clearReport()
 		if undone == 2 {
-			u.flashMsg("Undid your last move")
+			u.flashMsg(tr("Undid your last move"))
 		} else {
-			u.flashMsg("Undid move")
+			u.flashMsg(tr("Undid move"))
 		}
 	}
 }
 
 func (u *uiGame) startAIMove() {
-	if u.aiPending {
+	if u.aiPending || len(u.allLegalMoves()) == 0 {
 		return
 	}
+	if mv, ok := u.bookMove(); ok {
+		if err := u.makeMove(mv); err == nil {
+			u.lastMove = &mv
+			u.movesSAN = u.gameSAN()
+			return
+		}
+	}
 	u.aiPending = true
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	eng, timeout := u.handicapEngine(), u.aiMoveTime
+	if u.endgame != nil {
+		eng, timeout = u.endgame.ai, aiMoveTimes[0] // drills are played at full strength
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	u.aiCtxCancel = cancel
 	gameCopy := u.g // using game directly; assumption: no concurrent human move until AI done
 	go func() {
-		mv, err := u.aiEngine.GetBestMove(ctx, gameCopy)
+		mv, ok := u.tablebaseMove()
+		var err error
+		if !ok {
+			mv, err = eng.GetBestMove(ctx, gameCopy)
+		}
+		if err != nil {
+			// out of time: play the best quick move rather than search again
+			if fb, found := fallbackMove(gameCopy); found {
+				mv, err = fb, nil
+			}
+		}
 		if err == nil {
-			_ = u.g.MakeMove(mv)
+			_ = u.makeMove(mv)
 			u.lastMove = &mv
-			u.movesSAN = u.g.GenerateSAN()
+			u.movesSAN = u.gameSAN()
 		}
 		u.aiPending = false
 		cancel()
@@ -524,36 +752,50 @@ func (u *uiGame) startAIMove() {
 }
 
 func (u *uiGame) cycleDifficulty() {
-	order := []ai.Difficulty{ai.DifficultyBeginner, ai.DifficultyEasy, ai.DifficultyMedium, ai.DifficultyHard, ai.DifficultyExpert}
 	idx := 0
-	for i, d := range order {
+	for i, d := range difficultyOrder {
 		if d == u.difficulty {
-			idx = (i + 1) % len(order)
+			idx = (i + 1) % len(difficultyOrder)
 			break
 		}
 	}
-	u.difficulty = order[idx]
+	u.difficulty = difficultyOrder[idx]
 	u.aiEngine = ai.NewMinimaxAI(u.difficulty)
-	u.flashMsg("Difficulty: " + u.difficultyLabel())
+	u.flashMsg(trf("Difficulty: %s", tr(u.difficultyLabel())))
 }
 
 func (u *uiGame) resetGame(color engine.Color) {
-	u.g = engine.NewGame()
+	u.aiMoveTime = u.nextAITime
+	u.applyHandicap()
+	g, err := newGameFrom(u.startFEN)
+	if err != nil {
+		u.startFEN, u.chess960 = "", false
+		g = engine.NewGame()
+	}
+	u.g, u.before, u.beforeSAN = g, nil, nil
 	u.selected = nil
 	u.lastMove = nil
 	u.movesSAN = nil
 	u.evalScore = nil
 	u.aiPending = false
-	u.flashMsg("New game (" + color.String() + ")")
+	u.clearHints()
+	u.leaveView()
+	u.marks = nil
+	u.premoves, u.premoveFrom = nil, nil
+	u.dropPendingMove()
+	u.resetEvalHistory(0)
+	u.clearReport()
+	u.openingPly, u.tbPly, u.tbWDLPly = -1, -1, -1
+	u.flashMsg(trf("New game (%s)", tr(color.String())))
 }
 
 func (u *uiGame) flashMsg(m string) { u.msg = m; u.msgUntil = time.Now().Add(2 * time.Second) }
 
 func (u *uiGame) modeString() string {
 	if u.mode == HumanVsAI {
-		return "Human vs AI"
+		return tr("Human vs AI")
 	}
-	return "Human vs Human"
+	return tr("Human vs Human")
 }
 
 func (u *uiGame) difficultyLabel() string {
@@ -591,8 +833,11 @@ func (u *uiGame) difficultyCustomLabel(d ai.Difficulty) string {
 	}
 }
 
+// training reports whether a puzzle or repertoire drill drives the opponent's moves.
+func (u *uiGame) training() bool { return u.puzzle != nil || u.drill != nil }
+
 func (u *uiGame) aiColor() engine.Color {
-	if u.mode != HumanVsAI {
+	if u.mode != HumanVsAI && u.endgame == nil {
 		return engine.None
 	}
 	if u.playerColor == engine.White {
@@ -797,7 +1042,7 @@ func drawSelectableBox(screen *ebiten.Image, x, y, w, h int, label string, selec
 	img := ebiten.NewImage(w, h)
 	img.Fill(bg)
 	screen.DrawImage(img, &ebiten.DrawImageOptions{GeoM: translate(x, y)})
-	ebitenutil.DebugPrintAt(screen, label, x+6, y+3)
+	drawText(screen, label, panelFace, x+6, y+(h-lineHeight)/2+1, color.White)
 }
 
 // --- helpers
@@ -834,7 +1079,7 @@ func fmtInt(v int) string {
 
 func evalString(cp int) string {
 	// Convert centipawns to approximate score with sign indicator
-	return "Eval: " + fmtInt(cp) + " cp"
+	return trf("Eval: %s cp", fmtInt(cp))
 }
 
 func main() {
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"go.rumenx.com/chess/engine"
+)
+
+const maxTypedLen = 12
+
+// handleTyping runs the typed move box: "/" opens it, Enter plays the move
+// and keeps the box open for the next one, Esc closes it. It reports whether
+// the box owns the keyboard this frame, in which case the other shortcuts
+// are skipped.
+func (u *uiGame) handleTyping() bool {
+	if !u.typing {
+		if inpututil.IsKeyJustPressed(ebiten.KeySlash) {
+			u.typing, u.typed, u.typedErr = true, "", ""
+			return true
+		}
+		return false
+	}
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		u.typing, u.typed, u.typedErr = false, "", ""
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter):
+		u.submitTyped()
+	case inpututil.IsKeyJustPressed(ebiten.KeyBackspace):
+		if u.typed != "" {
+			u.typed = u.typed[:len(u.typed)-1]
+		}
+	default:
+		for _, r := range ebiten.AppendInputChars(nil) {
+			if r > ' ' && r < 0x7F && len(u.typed) < maxTypedLen {
+				u.typed += string(r)
+				u.typedErr = ""
+			}
+		}
+	}
+	return true
+}
+
+// submitTyped plays the move in the box; parse errors stay in the panel
+// until the next edit.
+func (u *uiGame) submitTyped() {
+	text := strings.TrimSpace(u.typed)
+	u.typed = ""
+	if text == "" {
+		return
+	}
+	if u.aiPending || u.g.ActiveColor() == u.aiColor() {
+		u.typedErr = tr("not your turn")
+		return
+	}
+	u.leaveView()
+	mv, err := parseTypedMove(u.g, u.chess960Castles(), text)
+	if err != nil {
+		u.typedErr = err.Error()
+		u.playSound(soundIllegal)
+		return
+	}
+	u.typedErr = ""
+	u.selected = nil
+	if isPromotion(mv) && !u.autoQueen && !promotionTyped(text) {
+		u.askPromotion(mv)
+		return
+	}
+	u.applyMove(mv)
+}
+
+// promotionTyped reports whether a typed move names its promotion piece: a
+// move ends with its target rank unless a piece letter follows.
+func promotionTyped(text string) bool {
+	s := strings.TrimRight(text, "+#!? ")
+	return s != "" && (s[len(s)-1] < '1' || s[len(s)-1] > '8')
+}
+
+// typingLines returns the panel lines of the move box; nothing when it is closed.
+func (u *uiGame) typingLines() []string {
+	if !u.typing {
+		return nil
+	}
+	lines := []string{tr("Move> ") + u.typed + "_"}
+	if u.typedErr != "" {
+		lines = append(lines, wrapText(u.typedErr, panelTextWidth)...)
+	}
+	return lines
+}
+
+var promotionPieces = map[byte]engine.PieceType{'q': engine.Queen, 'r': engine.Rook, 'b': engine.Bishop, 'n': engine.Knight}
+
+// parseTypedMove reads a move in UCI (e2e4, e7e8q), long algebraic
+// (Ng1-f3, e7-e8=Q) or SAN. SAN is matched loosely: capture and check marks
+// are optional, piece letters may be lower case, castling may use zeros and
+// a missing promotion piece means a queen (submitTyped asks for the piece
+// instead when auto-queen is off). An under-specified piece move is
+// reported together with the moves it could mean. castles are the Chess960
+// castling moves the app plays itself (see chess960Castles).
+func parseTypedMove(g *engine.Game, castles []engine.Move, text string) (engine.Move, error) {
+	if mv, ok := coordinateMove(g, text); ok {
+		return mv, nil
+	}
+	legal := append(g.GetAllLegalMoves(), castles...)
+	sans := make([]string, len(legal))
+	for i, mv := range legal {
+		if i >= len(legal)-len(castles) {
+			if after, err := castleChess960(g, mv); err == nil {
+				sans[i] = castleSAN(after, mv)
+			}
+			continue
+		}
+		if g.MakeMove(mv) != nil {
+			continue
+		}
+		all := g.GenerateSAN()
+		_, _ = g.UndoMove()
+		if len(all) > 0 {
+			sans[i] = all[len(all)-1]
+		}
+	}
+	for _, variant := range sanVariants(text) {
+		key := sanKey(variant)
+		var exact, loose []int
+		for i, san := range sans {
+			k := sanKey(san)
+			switch {
+			case san == "":
+			case k == key:
+				exact = append(exact, i)
+			case k == key+"Q" || (isPieceLetter(k) && len(k) > 3 && k[:1]+k[len(k)-2:] == key):
+				loose = append(loose, i)
+			}
+		}
+		switch {
+		case len(exact) == 1:
+			return legal[exact[0]], nil
+		case len(exact) == 0 && len(loose) == 1:
+			return legal[loose[0]], nil
+		case len(exact)+len(loose) > 1:
+			var options []string
+			for _, i := range append(exact, loose...) {
+				options = append(options, localizeSAN(sans[i]))
+			}
+			return engine.Move{}, errors.New(trf("ambiguous %s: %s", text, strings.Join(options, tr(" or "))))
+		}
+	}
+	return engine.Move{}, errors.New(trf("no legal move %s", text))
+}
+
+// coordinateMove reads UCI and long algebraic notation through the engine's
+// move parser; pawns reaching the last rank become queens unless a piece is
+// given, and submitTyped asks for the piece when auto-queen is off.
+func coordinateMove(g *engine.Game, text string) (engine.Move, bool) {
+	s := strings.ToLower(strings.TrimRight(text, "+#!?"))
+	if len(s) > 2 && strings.IndexByte("kqrbn", s[0]) >= 0 && s[1] >= 'a' && s[1] <= 'h' {
+		s = s[1:] // piece letter of long algebraic notation
+	}
+	s = strings.NewReplacer("-", "", "x", "", "=", "").Replace(s)
+	if len(s) != 4 && len(s) != 5 {
+		return engine.Move{}, false
+	}
+	for i := 0; i < 4; i += 2 {
+		if s[i] < 'a' || s[i] > 'h' || s[i+1] < '1' || s[i+1] > '8' {
+			return engine.Move{}, false
+		}
+	}
+	promo := engine.Queen
+	if len(s) == 5 {
+		p, ok := promotionPieces[s[4]]
+		if !ok {
+			return engine.Move{}, false
+		}
+		promo = p
+	}
+	mv, err := g.ParseMove(s[:4])
+	if err == nil && mv.Piece.Type == engine.Pawn && (mv.To.Rank() == 7 || mv.To.Rank() == 0) {
+		mv.Type = engine.Promotion
+		mv.Promotion = promo
+	}
+	if err == nil && g.IsLegalMove(mv) {
+		return mv, true
+	}
+	if len(s) == 4 {
+		if mv, ok := findUCIMove(g, s); ok {
+			return mv, true
+		}
+		s += "q"
+	}
+	return findUCIMove(g, s)
+}
+
+// sanKey strips the marks that may be left out when typing SAN: "exd8=Q+"
+// becomes "ed8Q".
+func sanKey(san string) string {
+	return strings.NewReplacer("x", "", ":", "", "=", "", "-", "").Replace(normalizeSAN(san))
+}
+
+func isPieceLetter(key string) bool { return key != "" && strings.IndexByte("KQRBN", key[0]) >= 0 }
+
+// sanVariants returns the readings of typed SAN to try in order. Piece
+// letters of the display language come first; a lower-case English piece
+// letter is accepted, but "b" is tried as a pawn file first.
+func sanVariants(text string) []string {
+	if strings.HasPrefix(strings.ToLower(text), "o-o") || strings.HasPrefix(text, "0-0") {
+		return []string{strings.ToUpper(strings.ReplaceAll(text, "0", "O"))}
+	}
+	variants := []string{text}
+	if d := delocalizeSAN(text); d != text {
+		variants = []string{d, text}
+	}
+	if text != "" && strings.IndexByte("kqrbn", text[0]) >= 0 {
+		variants = append(variants, strings.ToUpper(text[:1])+text[1:])
+	}
+	return variants
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const figurineSettingKey = "figurines"
+
+// figurineSAN replaces the piece letters of SAN, promotions included, with
+// chess symbols; files are lower case and stay as they are.
+var figurineSAN = strings.NewReplacer("K", "♔", "Q", "♕", "R", "♖", "B", "♗", "N", "♘")
+
+// toggleFigurines switches between piece letters and figurines and remembers the choice.
+func (u *uiGame) toggleFigurines() {
+	u.toggleFlag(figurineSettingKey, &u.figurines, "Figurine notation on", "Figurine notation off")
+}
+
+// sanText returns SAN as displayed: with figurines when they are on, else
+// with the display language's piece letters.
+func (u *uiGame) sanText(san string) string {
+	if u.figurines {
+		return figurineSAN.Replace(san)
+	}
+	return localizeSAN(san)
+}
+
+// moveList is the game score in numbered rows, "1. e4 e5". The mouse wheel
+// scrolls it, the move leading to the displayed position is highlighted and
+// clicking a move shows the position after it (see view.go). Like a
+// following textBox it keeps the last moves in view until scrolled back.
+type moveList struct {
+	widgetBase
+	u      *uiGame
+	offset int // rows scrolled back from the end
+	shown  int // displayed ply at the last draw
+}
+
+// numbering returns the move number of the first row and whether the game
+// began with Black to move, in which case that row opens with "...".
+func (m *moveList) numbering() (first int, blackFirst bool) {
+	return startNumbering(m.u.startFEN)
+}
+
+// shape returns the move number of the first row, the number of empty
+// slots before the first move and the number of rows.
+func (m *moveList) shape() (firstNum, shift, rows int) {
+	firstNum, blackFirst := m.numbering()
+	if blackFirst {
+		shift = 1
+	}
+	return firstNum, shift, (len(m.u.movesSAN) + shift + 1) / 2
+}
+
+// columns returns the x coordinates of the White and Black moves.
+func (m *moveList) columns(firstNum, rows int) (white, black int) {
+	numW := textWidth(strconv.Itoa(firstNum+rows)+".", panelFace) + 6
+	colW := (m.rect.Dx() - numW - 6) / 2
+	white = m.rect.Min.X + numW
+	return white, white + colW
+}
+
+// top returns the first visible row of rows and clamps offset.
+func (m *moveList) top(rows int) int {
+	extra := max(rows-m.rect.Dy()/lineHeight, 0)
+	m.offset = min(max(m.offset, 0), extra)
+	return extra - m.offset
+}
+
+// cell returns the rectangle of the move in slot s, counting White and
+// Black moves alternately from the first row.
+func (m *moveList) cell(s, top, whiteX, blackX int) image.Rectangle {
+	y := m.rect.Min.Y + (s/2-top)*lineHeight
+	if s%2 == 0 {
+		return image.Rect(whiteX-3, y, blackX-3, y+lineHeight)
+	}
+	return image.Rect(blackX-3, y, m.rect.Max.X-6, y+lineHeight)
+}
+
+func (m *moveList) draw(screen *ebiten.Image, hover bool) {
+	n := len(m.u.movesSAN)
+	if m.rect.Empty() || n == 0 {
+		return
+	}
+	firstNum, shift, rows := m.shape()
+	visible := m.rect.Dy() / lineHeight
+	cur := m.u.displayPly() - 1
+	if cur != m.shown && m.u.viewing() {
+		// bring a move chosen elsewhere (PageUp, the graph) into view
+		row := (cur + shift) / 2
+		start := m.top(rows)
+		if row < start {
+			m.offset += start - row
+		} else if row >= start+visible {
+			m.offset -= row - start - visible + 1
+		}
+	}
+	m.shown = cur
+	top := m.top(rows)
+	whiteX, blackX := m.columns(firstNum, rows)
+	cx, cy := ebiten.CursorPosition()
+	clip := screen.SubImage(m.rect).(*ebiten.Image)
+	for row := top; row < rows && row < top+visible; row++ {
+		y := m.rect.Min.Y + (row-top)*lineHeight
+		printText(clip, strconv.Itoa(firstNum+row)+".", m.rect.Min.X, y)
+		for s := row * 2; s < row*2+2; s++ {
+			i := s - shift
+			r := m.cell(s, top, whiteX, blackX)
+			switch {
+			case i < 0:
+				printText(clip, "...", r.Min.X+3, y)
+				continue
+			case i >= n:
+				continue
+			case i == cur:
+				vector.DrawFilledRect(clip, float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), color.RGBA{90, 120, 200, 0xFF}, false)
+			case hover && image.Pt(cx, cy).In(r):
+				vector.DrawFilledRect(clip, float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), color.RGBA{60, 60, 60, 0xFF}, false)
+			}
+			printText(clip, m.u.sanText(m.u.reviewedSAN(i)), r.Min.X+3, y)
+		}
+	}
+	if rows > visible {
+		// scroll thumb at the right edge
+		h := float32(m.rect.Dy()) * float32(visible) / float32(rows)
+		y := float32(m.rect.Min.Y) + float32(m.rect.Dy())*float32(top)/float32(rows)
+		vector.DrawFilledRect(screen, float32(m.rect.Max.X-3), y, 3, h, color.RGBA{0x88, 0x88, 0x88, 0xFF}, false)
+	}
+}
+
+func (m *moveList) click(x, y int) {
+	n := len(m.u.movesSAN)
+	if n == 0 {
+		return
+	}
+	firstNum, shift, rows := m.shape()
+	top := m.top(rows)
+	_, blackX := m.columns(firstNum, rows)
+	s := (top + (y-m.rect.Min.Y)/lineHeight) * 2
+	if x >= blackX-3 {
+		s++
+	}
+	if i := s - shift; i >= 0 && i < n {
+		// the last move is the live position, which leaves review
+		m.u.setViewPly(i + 1)
+	}
+}
+
+func (m *moveList) scroll(dy float64) { m.offset += int(dy) }
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.rumenx.com/chess/ai"
+	"go.rumenx.com/chess/engine"
+)
+
+// oddsKind is the material the AI side gives up at the start of a game.
+type oddsKind int
+
+const (
+	oddsNone oddsKind = iota
+	oddsPawn
+	oddsKnight
+	oddsRook
+	oddsQueen
+)
+
+// oddsSquare is the traditional piece removed for each odds, as file and
+// piece letter on the giver's back rank (pawn odds: the f-pawn).
+var oddsSquare = map[oddsKind]struct {
+	file  int
+	piece byte
+}{
+	oddsPawn:   {5, 'P'},
+	oddsKnight: {1, 'N'},
+	oddsRook:   {0, 'R'},
+	oddsQueen:  {3, 'Q'},
+}
+
+func (o oddsKind) String() string {
+	switch o {
+	case oddsPawn:
+		return "Pawn"
+	case oddsKnight:
+		return "Knight"
+	case oddsRook:
+		return "Rook"
+	case oddsQueen:
+		return "Queen"
+	}
+	return "None"
+}
+
+// aiMoveTimes are the selectable AI thinking times; the first is the default,
+// shorter times handicap the AI.
+var aiMoveTimes = []time.Duration{5 * time.Second, 2 * time.Second, time.Second, 500 * time.Millisecond}
+
+// aiTimeDifficulty is the strongest search each of aiMoveTimes allows. The
+// minimax searches to a fixed depth, so a shorter deadline alone would not
+// weaken a search that finishes in time.
+var aiTimeDifficulty = []ai.Difficulty{ai.DifficultyExpert, ai.DifficultyHard, ai.DifficultyMedium, ai.DifficultyEasy}
+
+// handicapEngine returns the engine for the AI's next move: the selected
+// difficulty, capped by the thinking time.
+func (u *uiGame) handicapEngine() ai.Engine {
+	for i, d := range aiMoveTimes {
+		if d == u.aiMoveTime && aiTimeDifficulty[i] < u.difficulty {
+			return ai.NewMinimaxAI(aiTimeDifficulty[i])
+		}
+	}
+	return u.aiEngine
+}
+
+// fallbackMove returns the legal move with the best two-ply score, played
+// when the AI's search runs out of time.
+func fallbackMove(g *engine.Game) (engine.Move, bool) {
+	var best engine.Move
+	bestScore, found := 0, false
+	for _, mv := range g.GetAllLegalMoves() {
+		if g.MakeMove(mv) != nil {
+			continue
+		}
+		s := -negamax(g, 1, -mateScore-1, mateScore+1, 1)
+		_, _ = g.UndoMove()
+		if !found || s > bestScore {
+			best, bestScore, found = mv, s, true
+		}
+	}
+	return best, found
+}
+
+// standardFEN is the regular start position.
+const standardFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+// oddsFEN returns the start position with giver's odds piece removed.
+func oddsFEN(odds oddsKind, giver engine.Color) string {
+	pos, err := parseFEN(standardFEN)
+	spec, ok := oddsSquare[odds]
+	if err != nil || !ok {
+		return ""
+	}
+	row, piece, castle := 0, spec.piece, "Q"
+	if spec.piece == 'P' {
+		row = 8
+	}
+	if giver == engine.Black {
+		row = 56 - row
+		piece += 'a' - 'A'
+		castle = "q"
+	}
+	if pos.board[row+spec.file] != piece {
+		return ""
+	}
+	pos.board[row+spec.file] = 0
+	castling := "KQkq"
+	if odds == oddsRook {
+		castling = strings.Replace(castling, castle, "", 1)
+	}
+	return pos.placement() + " w " + castling + " - 0 1"
+}
+
+// oddsGiver is the side giving odds: the AI side, i.e. the player's opponent.
+func (u *uiGame) oddsGiver() engine.Color {
+	if u.playerColor == engine.White {
+		return engine.Black
+	}
+	return engine.White
+}
+
+// applyHandicap sets the start position for the selected odds; called by resetGame.
+func (u *uiGame) applyHandicap() {
+	if u.odds != oddsNone {
+		u.startFEN = oddsFEN(u.odds, u.oddsGiver())
+	}
+}
+
+// cycleOdds selects the next material odds and starts a new game with it.
+func (u *uiGame) cycleOdds() {
+	u.odds = (u.odds + 1) % (oddsQueen + 1)
+	u.chess960, u.puzzle, u.drill, u.endgame = false, nil, nil, nil
+	u.startFEN = ""
+	u.resetGame(u.playerColor)
+	u.flashMsg(trf("Odds: %s", tr(u.odds.String())))
+}
+
+// cycleAITime selects the next AI thinking time. A game in progress keeps
+// its time; the new one applies from the next game.
+func (u *uiGame) cycleAITime() {
+	idx := 0
+	for i, d := range aiMoveTimes {
+		if d == u.nextAITime {
+			idx = (i + 1) % len(aiMoveTimes)
+			break
+		}
+	}
+	u.nextAITime = aiMoveTimes[idx]
+	if u.ply() > 0 && !u.training() && u.endgame == nil {
+		u.flashMsg(trf("AI time: %s from the next game", u.nextAITime))
+		return
+	}
+	u.aiMoveTime = u.nextAITime
+	u.flashMsg(trf("AI time: %s", u.aiMoveTime))
+}
+
+// handicapLabel describes the active handicaps, or "" when playing even;
+// t translates the words (untranslated for PGN tags).
+func (u *uiGame) handicapLabel(t func(string) string) string {
+	var parts []string
+	if u.odds != oddsNone {
+		parts = append(parts, fmt.Sprintf(t("%s odds by %s"), t(u.odds.String()), t(u.oddsGiver().String())))
+	}
+	if u.aiMoveTime != aiMoveTimes[0] {
+		parts = append(parts, fmt.Sprintf(t("AI %s/move"), u.aiMoveTime))
+	}
+	return strings.Join(parts, ", ")
+}
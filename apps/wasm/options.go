@@ -0,0 +1,174 @@
+package main
+
+import (
+	"image/color"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"go.rumenx.com/chess/engine"
+)
+
+// Keys of the on/off settings saved between sessions.
+const (
+	confirmMovesSettingKey = "confirm-moves"
+	autoQueenSettingKey    = "auto-queen"
+	showTargetsSettingKey  = "show-targets"
+)
+
+// promotionChoices are the pieces offered by the promotion picker, from the
+// promotion square towards the middle of the board.
+var promotionChoices = []engine.PieceType{engine.Queen, engine.Knight, engine.Rook, engine.Bishop}
+
+var pendingMoveColor = color.RGBA{0xE0, 0xB0, 0x20, 0x70}
+
+// loadFlag returns the saved on/off setting under key, or def when there is none.
+func loadFlag(key string, def bool) bool {
+	v, ok := loadSetting(key)
+	if !ok {
+		return def
+	}
+	return v == "1"
+}
+
+// toggleFlag flips *on, saves it under key and flashes the matching message.
+func (u *uiGame) toggleFlag(key string, on *bool, onMsg, offMsg string) {
+	*on = !*on
+	v := "0"
+	if *on {
+		v = "1"
+	}
+	if err := saveSetting(key, v); err != nil {
+		log.Printf("[ERROR] Saving %s setting: %v", key, err)
+	}
+	if *on {
+		u.flashMsg(tr(onMsg))
+	} else {
+		u.flashMsg(tr(offMsg))
+	}
+}
+
+// toggleConfirmMoves switches the second click that confirms a board move.
+func (u *uiGame) toggleConfirmMoves() {
+	u.toggleFlag(confirmMovesSettingKey, &u.confirmMoves, "Confirm moves on", "Confirm moves off")
+	u.confirming = nil
+}
+
+// toggleAutoQueen switches between queening at once and the promotion picker.
+func (u *uiGame) toggleAutoQueen() {
+	u.toggleFlag(autoQueenSettingKey, &u.autoQueen, "Auto-queen on", "Auto-queen off")
+}
+
+// toggleShowTargets shows or hides the dots on the selected piece's targets.
+func (u *uiGame) toggleShowTargets() {
+	u.toggleFlag(showTargetsSettingKey, &u.showTargets, "Legal targets shown", "Legal targets hidden")
+}
+
+// isPromotion reports whether mv takes a pawn to the last rank.
+func isPromotion(mv engine.Move) bool {
+	return mv.Piece.Type == engine.Pawn && (mv.To.Rank() == 7 || mv.To.Rank() == 0)
+}
+
+// chooseMove plays a move picked on the board, once the promotion piece is
+// picked and the move confirmed when the options ask for that.
+func (u *uiGame) chooseMove(mv engine.Move) {
+	if isPromotion(mv) {
+		if !u.autoQueen {
+			u.promoting = &mv
+			return
+		}
+		mv = autoQueen(mv)
+	}
+	u.confirmOrPlay(mv)
+}
+
+// askPromotion opens the promotion picker for a typed move or premove that
+// doesn't name its piece while auto-queen is off.
+func (u *uiGame) askPromotion(mv engine.Move) {
+	u.promoting = &mv
+	u.flashMsg(tr("Pick the promotion piece"))
+}
+
+// confirmOrPlay plays mv, or holds it for a second click when moves are confirmed.
+func (u *uiGame) confirmOrPlay(mv engine.Move) {
+	if u.confirmMoves {
+		u.confirming = &mv
+		u.flashMsg(trf("Click %s again to play, elsewhere to cancel", mv.To))
+		return
+	}
+	u.applyMove(mv)
+}
+
+// promotionSquares returns the squares of the promotion picker for a pawn
+// promoting on to, one per entry of promotionChoices.
+func promotionSquares(to engine.Square) []engine.Square {
+	step := -8
+	if to.Rank() == 0 {
+		step = 8
+	}
+	squares := make([]engine.Square, len(promotionChoices))
+	for i := range squares {
+		squares[i] = to + engine.Square(i*step)
+	}
+	return squares
+}
+
+// clickPromotion finishes the pending promotion with the piece on sq, or
+// drops it when sq is not in the picker.
+func (u *uiGame) clickPromotion(sq engine.Square) {
+	mv := *u.promoting
+	u.promoting = nil
+	for i, s := range promotionSquares(mv.To) {
+		if s == sq {
+			mv.Type = engine.Promotion
+			mv.Promotion = promotionChoices[i]
+			u.confirmOrPlay(mv)
+			return
+		}
+	}
+}
+
+// clickConfirm plays the move waiting for confirmation when its target is
+// clicked again; any other square drops it.
+func (u *uiGame) clickConfirm(sq engine.Square) {
+	mv := *u.confirming
+	u.confirming = nil
+	if sq == mv.To {
+		u.applyMove(mv)
+		return
+	}
+	u.flashMsg(tr("Move cancelled"))
+}
+
+// dropPendingMove forgets a move waiting for its promotion piece or confirmation.
+func (u *uiGame) dropPendingMove() {
+	u.promoting, u.confirming = nil, nil
+}
+
+// drawPendingMove shows the move waiting for confirmation as a faded piece
+// on its target, or the promotion picker.
+func (u *uiGame) drawPendingMove(board *ebiten.Image) {
+	if mv := u.confirming; mv != nil {
+		u.highlightSquare(board, mv.From, pendingMoveColor)
+		u.highlightSquare(board, mv.To, pendingMoveColor)
+		p := mv.Piece
+		if mv.Type == engine.Promotion {
+			p.Type = mv.Promotion
+		}
+		u.drawPieceAt(board, p, mv.To, 0.6)
+	}
+	if mv := u.promoting; mv != nil {
+		for i, sq := range promotionSquares(mv.To) {
+			u.highlightSquare(board, sq, color.RGBA{0xF0, 0xF0, 0xF0, 0xE0})
+			u.drawPieceAt(board, engine.Piece{Type: promotionChoices[i], Color: mv.Piece.Color}, sq, 1)
+		}
+	}
+}
+
+// drawPieceAt draws piece p on sq with the given opacity.
+func (u *uiGame) drawPieceAt(board *ebiten.Image, p engine.Piece, sq engine.Square, alpha float32) {
+	cx, cy := u.squareCenter(sq)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(cx)-squareSize/2, float64(cy)-squareSize/2)
+	op.ColorScale.ScaleAlpha(alpha)
+	board.DrawImage(u.pieceImage(p), op)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"go.rumenx.com/chess/ai"
+	"go.rumenx.com/chess/engine"
+)
+
+// difficultyOrder is the order of the difficulty list and of Space cycling.
+var difficultyOrder = []ai.Difficulty{ai.DifficultyBeginner, ai.DifficultyEasy, ai.DifficultyMedium, ai.DifficultyHard, ai.DifficultyExpert}
+
+// panelRect converts a panel-relative rectangle to screen coordinates.
+func panelRect(x, y, w, h int) image.Rectangle {
+	return image.Rect(panelX+x, y, panelX+x+w, y+h)
+}
+
+// newPanel builds the side panel. The controls keep fixed places; the
+// info, move and help text flow between them (see the layout function).
+func (u *uiGame) newPanel() *panel {
+	mode := &button{widgetBase{panelRect(8, 8, 120, 20), "Switch between Human vs AI and Human vs Human"},
+		"Switch between Human vs AI and Human vs Human", u.modeString, nil, u.toggleMode}
+	hint := &button{widgetBase{panelRect(136, 8, 96, 20), "Suggest a move for the side to move"},
+		"Hint", func() string { return tr("Hint (H)") }, func() bool { return u.hintPending }, u.requestHint}
+	undo := &button{widgetBase{panelRect(8, 32, 120, 20), "Take back the last move"},
+		"Undo", func() string { return tr("Undo (U)") }, nil, u.handleUndo}
+	threat := &toggle{button{widgetBase{panelRect(136, 32, 96, 20), "Show what the opponent threatens"},
+		"Show threat", func() string { return tr("Threat (T)") }, nil, u.toggleThreat},
+		func() bool { return u.showThreat }}
+	colorOn := func(c engine.Color) func() bool {
+		return func() bool { return u.playerColor == c && u.ply() == 0 }
+	}
+	white := &toggle{button{widgetBase{panelRect(8, 56, 90, 20), "Choose your colour before the first move"},
+		"Play White", func() string { return tr("White") }, nil, func() { u.chooseColor(engine.White) }},
+		colorOn(engine.White)}
+	black := &toggle{button{widgetBase{panelRect(8, 81, 90, 20), "Choose your colour before the first move"},
+		"Play Black", func() string { return tr("Black") }, nil, func() { u.chooseColor(engine.Black) }},
+		colorOn(engine.Black)}
+	locked := &textBox{widgetBase: widgetBase{rect: panelRect(104, 64, 30, lineHeight)}, lines: func() []string {
+		if u.ply() == 0 {
+			return nil
+		}
+		return []string{tr("(locked)")}
+	}}
+	locked.rect.Max.X = locked.rect.Min.X + textWidth(tr("(locked)"), panelFace)
+	volume := &button{widgetBase{panelRect(136, 81, 96, 20), "Change the volume; M mutes"},
+		"Sound volume", u.volumeLabel, nil, u.cycleVolume}
+	diffLabel := &textBox{widgetBase: widgetBase{rect: panelRect(8, 115, panelTextWidth, lineHeight)},
+		lines: func() []string { return []string{tr("Difficulty:")} }}
+	diffs := &list{
+		widgetBase: widgetBase{panelRect(8, 135, 120, len(difficultyOrder)*22), "AI strength"},
+		items: func() []string {
+			items := make([]string, len(difficultyOrder))
+			for i, d := range difficultyOrder {
+				items[i] = tr(u.difficultyCustomLabel(d))
+			}
+			return items
+		},
+		selected: func() int {
+			for i, d := range difficultyOrder {
+				if d == u.difficulty {
+					return i
+				}
+			}
+			return -1
+		},
+		name:   func(i int) string { return trf("Difficulty %s", tr(u.difficultyCustomLabel(difficultyOrder[i]))) },
+		choose: func(i int) { u.setDifficulty(difficultyOrder[i]) },
+		rowH:   22,
+	}
+	confirm := &toggle{button{widgetBase{panelRect(136, 135, 96, 20), "Ask for a second click on the target before a move is played"},
+		"Confirm moves", func() string { return tr("Confirm") }, nil, u.toggleConfirmMoves},
+		func() bool { return u.confirmMoves }}
+	queen := &toggle{button{widgetBase{panelRect(136, 157, 96, 20), "Promote to a queen without asking"},
+		"Auto-queen", func() string { return tr("Auto-queen") }, nil, u.toggleAutoQueen},
+		func() bool { return u.autoQueen }}
+	targets := &toggle{button{widgetBase{panelRect(136, 179, 96, 20), "Mark the squares the selected piece can move to"},
+		"Show legal targets", func() string { return tr("Targets") }, nil, u.toggleShowTargets},
+		func() bool { return u.showTargets }}
+	info := &textBox{lines: u.infoLines}
+	movesTitle := &textBox{lines: func() []string { return []string{tr("Moves")} }}
+	figurines := &toggle{button{widgetBase{tooltip: "Show pieces as figurines (K)"},
+		"Figurine notation", func() string { return "♘" }, nil, u.toggleFigurines},
+		func() bool { return u.figurines }}
+	moves := &moveList{widgetBase: widgetBase{tooltip: "Click a move to review it; scroll for earlier moves"}, u: u, shown: -1}
+	graph := &graphWidget{widgetBase{tooltip: "Click to review the position after that move"}, u}
+	help := &textBox{lines: helpLines}
+
+	p := &panel{widgets: []widget{mode, hint, undo, threat, white, black, locked, volume, diffLabel, diffs, confirm, queen, targets, info, movesTitle, figurines, moves, graph, help}}
+	p.layout = func() {
+		gx, gy, gw, gh := graphRect()
+		graph.rect = panelRect(gx, gy, gw, gh)
+		help.rect = panelRect(8, helpTop(), panelTextWidth, windowH-helpTop())
+		// the info lines take what they need, leaving the moves at least two lines
+		top := diffs.rect.Max.Y + 16
+		room := gy - 8 - top - 16 - 3*lineHeight
+		info.rect = panelRect(8, top, panelTextWidth, min(len(info.lines())*lineHeight, max(room, 0)))
+		movesTitle.rect = panelRect(8, info.rect.Max.Y+12, panelTextWidth-40, lineHeight)
+		figurines.rect = panelRect(8+panelTextWidth-36, info.rect.Max.Y+10, 36, lineHeight+2)
+		moves.rect = image.Rect(panelX+8, movesTitle.rect.Max.Y+4, panelX+8+panelTextWidth, gy-4)
+	}
+	p.layout()
+	return p
+}
+
+// infoLines returns the status lines shown below the difficulty list.
+func (u *uiGame) infoLines() []string {
+	lines := []string{
+		trf("Status: %s", tr(u.g.Status().String())),
+		trf("Turn: %s", tr(u.g.ActiveColor().String())),
+		trf("Player: %s", tr(u.playerColor.String())),
+		trf("Diff: %s", tr(u.difficultyLabel())),
+		trf("Moves: %d", u.ply()),
+	}
+	lines = append(lines, u.puzzleLines()...)
+	lines = append(lines, u.drillLines()...)
+	lines = append(lines, u.endgameLines()...)
+	lines = append(lines, u.openingLines()...)
+	if u.chess960 {
+		lines = append(lines, trf("Chess960 #%d", u.chess960Pos))
+	}
+	if h := u.handicapLabel(tr); h != "" {
+		lines = append(lines, wrapText(trf("Handicap: %s", h), panelTextWidth)...)
+	}
+	if u.tbText != "" {
+		lines = append(lines, u.tbText)
+	}
+	if u.evalScore != nil {
+		lines = append(lines, evalString(*u.evalScore))
+	}
+	if u.blind != blindOff {
+		lines = append(lines, trf("Pieces: %s", tr(u.blind.String())))
+	}
+	lines = append(lines, u.typingLines()...)
+	if u.msg != "" {
+		lines = append(lines, wrapText(trf("Msg: %s", u.msg), panelTextWidth)...)
+	}
+	return lines
+}
+
+// graphWidget is the evaluation graph; clicking it shows the position at
+// that ply (see view.go).
+type graphWidget struct {
+	widgetBase
+	u *uiGame
+}
+
+func (g *graphWidget) draw(screen *ebiten.Image, hover bool) { g.u.drawEvalGraph(screen) }
+
+func (g *graphWidget) click(x, y int) {
+	n := len(g.u.evalHist)
+	if n < 2 {
+		return
+	}
+	step := float64(g.rect.Dx()) / float64(n-1)
+	g.u.setViewPly(int(math.Round(float64(x-g.rect.Min.X) / step)))
+}
+
+// helpKeys is the key help shown at the bottom of the panel.
+var helpKeys = []string{
+	"/=type V=pieces Z=peek Tab M=mute",
+	"9=960 [ ]=pos C=fen O=odds L=ai time",
+	"H=hint T=threat R=report X=pgn K=♘",
+	"Keys: N=new A=mode F=flip E=eval U=undo",
+	"P=puzzles D=drill G=endgames I=coords",
+}
+
+// helpLines returns the key help translated and wrapped to the panel.
+func helpLines() []string {
+	var lines []string
+	for _, k := range helpKeys {
+		lines = append(lines, wrapText(tr(k), panelTextWidth)...)
+	}
+	return lines
+}
+
+// helpTop is the y coordinate of the first key help line.
+func helpTop() int { return windowH - 8 - len(helpLines())*lineHeight }
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"go.rumenx.com/chess/engine"
+)
+
+// pgnTag is a single PGN header pair; tags are written in slice order.
+type pgnTag struct {
+	name  string
+	value string
+}
+
+// pgnAnnotator returns an optional suffix (e.g. "?!") and comment for ply i.
+type pgnAnnotator func(i int) (suffix, comment string)
+
+// startNumbering returns the move number of the first move of a game begun
+// from startFEN ("" for the standard position) and whether Black plays it.
+func startNumbering(startFEN string) (first int, blackFirst bool) {
+	if startFEN == "" {
+		return 1, false
+	}
+	pos, err := parseFEN(startFEN)
+	if err != nil {
+		return 1, false
+	}
+	return pos.fullmove, !pos.whiteToMove
+}
+
+// plyNumber returns the move number of ply i of a game begun from startFEN
+// and whether it is Black's move.
+func plyNumber(startFEN string, i int) (num int, black bool) {
+	first, blackFirst := startNumbering(startFEN)
+	if blackFirst {
+		i++
+	}
+	return first + i/2, i%2 == 1
+}
+
+// buildPGN renders header tags and SAN movetext, wrapping lines at 80 columns.
+// Moves are numbered from startFEN's side to move and fullmove number. intro,
+// when set, is a comment on the start position.
+func buildPGN(tags []pgnTag, startFEN string, san []string, result, intro string, annotate pgnAnnotator) string {
+	var b strings.Builder
+	for _, t := range tags {
+		b.WriteString("[" + t.name + " \"" + escapePGN(t.value) + "\"]\n")
+	}
+	b.WriteString("\n")
+
+	var tokens []string
+	if intro != "" {
+		tokens = append(tokens, "{ "+intro+" }")
+	}
+	for i, mv := range san {
+		n, black := plyNumber(startFEN, i)
+		num := stringFromInt(n)
+		switch {
+		case !black:
+			tokens = append(tokens, num+".")
+		case i == 0:
+			tokens = append(tokens, num+"...")
+		}
+		tok := mv
+		var comment string
+		if annotate != nil {
+			var suffix string
+			suffix, comment = annotate(i)
+			tok += suffix
+		}
+		tokens = append(tokens, tok)
+		if comment != "" {
+			tokens = append(tokens, "{ "+comment+" }")
+			if !black && i+1 < len(san) {
+				// Black's reply needs its move number again after a comment
+				tokens = append(tokens, num+"...")
+			}
+		}
+	}
+	tokens = append(tokens, result)
+
+	line := 0
+	for i, tok := range tokens {
+		if i > 0 {
+			if line+1+len(tok) > 80 {
+				b.WriteString("\n")
+				line = 0
+			} else {
+				b.WriteString(" ")
+				line++
+			}
+		}
+		b.WriteString(tok)
+		line += len(tok)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func escapePGN(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	return strings.ReplaceAll(s, "\"", "\\\"")
+}
+
+// pgnResult returns the PGN result token for the live game.
+func (u *uiGame) pgnResult() string {
+	if len(u.allLegalMoves()) > 0 {
+		return "*"
+	}
+	if !inCheck(u.g) {
+		return "1/2-1/2"
+	}
+	if u.g.ActiveColor() == engine.White {
+		return "0-1"
+	}
+	return "1-0"
+}
+
+// pgnTags returns the seven tag roster for the live game plus optional tags.
+func (u *uiGame) pgnTags() []pgnTag {
+	white, black := "Human", "Human"
+	if u.mode == HumanVsAI {
+		aiName := "go-chess AI (" + u.difficultyLabel() + ")"
+		if u.aiColor() == engine.White {
+			white = aiName
+		} else {
+			black = aiName
+		}
+	}
+	tags := []pgnTag{
+		{"Event", "Casual game"},
+		{"Site", "go-chess GUI"},
+		{"Date", time.Now().Format("2006.01.02")},
+		{"Round", "-"},
+		{"White", white},
+		{"Black", black},
+		{"Result", u.pgnResult()},
+	}
+	if u.chess960 {
+		tags = append(tags, pgnTag{"Variant", "Chess960"})
+	}
+	if h := u.handicapLabel(untranslated); h != "" {
+		tags = append(tags, pgnTag{"Handicap", h})
+	}
+	if u.startFEN != "" {
+		tags = append(tags, pgnTag{"SetUp", "1"}, pgnTag{"FEN", u.startFEN})
+	}
+	if u.opening != nil {
+		tags = append(tags, pgnTag{"ECO", u.opening.code}, pgnTag{"Opening", u.opening.name})
+	}
+	return tags
+}
+
+// exportPGN saves the current game, annotated when a report is available,
+// with the board arrows and circles as [%cal] and [%csl] commands.
+func (u *uiGame) exportPGN() {
+	reviewed := u.report != nil && len(u.report.reviews) == len(u.movesSAN)
+	annotate := func(i int) (suffix, comment string) {
+		if reviewed {
+			suffix, comment = u.report.annotate(i)
+		}
+		if marks := u.pgnMarks(i + 1); marks != "" {
+			comment = strings.TrimSpace(comment + " " + marks)
+		}
+		return suffix, comment
+	}
+	pgn := buildPGN(u.pgnTags(), u.startFEN, u.movesSAN, u.pgnResult(), u.pgnMarks(0), annotate)
+	name := "go-chess-" + time.Now().Format("20060102-150405") + ".pgn"
+	where, err := saveTextFile(name, pgn)
+	if err != nil {
+		u.flashMsg(tr("Export failed"))
+		return
+	}
+	u.flashMsg(trf("Saved %s", where))
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"go.rumenx.com/chess/engine"
+)
+
+// pgnNode is a move in a PGN move tree. The first child continues the main
+// line, further children are variations.
+type pgnNode struct {
+	san      string
+	comment  string
+	parent   *pgnNode
+	children []*pgnNode
+}
+
+// pgnGame is one parsed PGN game with its variations.
+type pgnGame struct {
+	tags []pgnTag
+	root *pgnNode // holds no move; its children are the first moves
+}
+
+var errBadPGN = errors.New("invalid PGN")
+
+// child returns the child playing san, or nil.
+func (n *pgnNode) child(san string) *pgnNode {
+	for _, c := range n.children {
+		if sameSAN(c.san, san) {
+			return c
+		}
+	}
+	return nil
+}
+
+// add returns the child playing san, creating it when missing.
+func (n *pgnNode) add(san string) *pgnNode {
+	if c := n.child(san); c != nil {
+		return c
+	}
+	c := &pgnNode{san: san, parent: n}
+	n.children = append(n.children, c)
+	return c
+}
+
+// line returns the moves from the root to n.
+func (n *pgnNode) line() []string {
+	var sans []string
+	for ; n != nil && n.parent != nil; n = n.parent {
+		sans = append(sans, n.san)
+	}
+	for i, j := 0, len(sans)-1; i < j; i, j = i+1, j-1 {
+		sans[i], sans[j] = sans[j], sans[i]
+	}
+	return sans
+}
+
+// normalizeSAN strips check marks and annotation glyphs so SAN from
+// different sources compares equal.
+func normalizeSAN(san string) string {
+	san = strings.TrimRight(san, "+#!?")
+	return strings.ReplaceAll(san, "0", "O")
+}
+
+func sameSAN(a, b string) bool { return normalizeSAN(a) == normalizeSAN(b) }
+
+// parsePGN reads all games of a PGN text, keeping comments and variations.
+func parsePGN(text string) ([]*pgnGame, error) {
+	var games []*pgnGame
+	var game *pgnGame
+	var stack []*pgnNode // position before the last move, per variation level
+	var cur *pgnNode     // node of the last move played
+	startGame := func() {
+		game = &pgnGame{root: &pgnNode{}}
+		games = append(games, game)
+		cur, stack = game.root, nil
+	}
+	i := 0
+	for i < len(text) {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '[':
+			end := strings.IndexByte(text[i:], ']')
+			if end < 0 {
+				return nil, errBadPGN
+			}
+			if game == nil || len(game.root.children) > 0 {
+				startGame()
+			}
+			tag := strings.TrimSpace(text[i+1 : i+end])
+			if sp := strings.IndexByte(tag, ' '); sp > 0 {
+				value := strings.Trim(strings.TrimSpace(tag[sp+1:]), "\"")
+				game.tags = append(game.tags, pgnTag{tag[:sp], strings.ReplaceAll(value, "\\\"", "\"")})
+			}
+			i += end + 1
+		case c == '{':
+			end := strings.IndexByte(text[i:], '}')
+			if end < 0 {
+				return nil, errBadPGN
+			}
+			if cur != nil {
+				cur.comment = strings.TrimSpace(cur.comment + " " + strings.TrimSpace(text[i+1:i+end]))
+			}
+			i += end + 1
+		case c == ';':
+			end := strings.IndexByte(text[i:], '\n')
+			if end < 0 {
+				end = len(text) - i
+			}
+			i += end
+		case c == '(':
+			if cur == nil || cur.parent == nil {
+				return nil, errBadPGN
+			}
+			// a variation replaces the last move
+			stack = append(stack, cur)
+			cur = cur.parent
+			i++
+		case c == ')':
+			if len(stack) == 0 {
+				return nil, errBadPGN
+			}
+			cur = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			i++
+		default:
+			j := i
+			for j < len(text) && !strings.ContainsRune(" \t\r\n{}();[", rune(text[j])) {
+				j++
+			}
+			tok := text[i:j]
+			i = j
+			if game == nil {
+				startGame()
+			}
+			switch {
+			case tok == "1-0" || tok == "0-1" || tok == "1/2-1/2" || tok == "*":
+				if len(stack) == 0 {
+					game = nil
+				}
+			case tok[0] == '$':
+				// NAG, ignored
+			case tok[0] >= '0' && tok[0] <= '9' && strings.Trim(tok, "0123456789.") == "":
+				// move number
+			default:
+				if k := strings.LastIndexByte(tok, '.'); k >= 0 {
+					tok = tok[k+1:] // "12.e4" style
+				}
+				if tok == "" {
+					continue
+				}
+				cur = cur.add(tok)
+			}
+		}
+	}
+	if len(games) == 0 {
+		return nil, errBadPGN
+	}
+	return games, nil
+}
+
+// sanMove finds the legal move of g written as san.
+func sanMove(g *engine.Game, san string) (engine.Move, bool) {
+	for _, mv := range g.GetAllLegalMoves() {
+		if g.MakeMove(mv) != nil {
+			continue
+		}
+		sans := g.GenerateSAN()
+		_, _ = g.UndoMove()
+		if len(sans) > 0 && sameSAN(sans[len(sans)-1], san) {
+			return mv, true
+		}
+	}
+	return engine.Move{}, false
+}
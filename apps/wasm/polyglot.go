@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"go.rumenx.com/chess/engine"
+)
+
+// Offsets into polyglotRandom as defined by the Polyglot book format.
+const (
+	polyglotCastleOffset    = 768
+	polyglotEnPassantOffset = 772
+	polyglotTurnOffset      = 780
+	polyglotEntrySize       = 16
+)
+
+// polyglotPieceKind maps FEN letters to the Polyglot piece index
+// (black pawn 0, white pawn 1, black knight 2, ... white king 11).
+var polyglotPieceKind = map[byte]int{
+	'p': 0, 'P': 1, 'n': 2, 'N': 3, 'b': 4, 'B': 5,
+	'r': 6, 'R': 7, 'q': 8, 'Q': 9, 'k': 10, 'K': 11,
+}
+
+// polyglotKey computes the standard Polyglot Zobrist key of a FEN position.
+func polyglotKey(fen string) (uint64, error) {
+	pos, err := parseFEN(fen)
+	if err != nil {
+		return 0, err
+	}
+	var key uint64
+	for sq, c := range pos.board {
+		if c != 0 {
+			key ^= polyglotRandom[64*polyglotPieceKind[c]+sq]
+		}
+	}
+	for i, flag := range "KQkq" {
+		if strings.ContainsRune(pos.castling, flag) {
+			key ^= polyglotRandom[polyglotCastleOffset+i]
+		}
+	}
+	// the en passant file only counts when a pawn can actually capture
+	if ep := pos.epSquare; ep >= 0 {
+		pawn, row := byte('p'), 3
+		if pos.whiteToMove {
+			pawn, row = 'P', 4
+		}
+		file := ep % 8
+		for _, df := range []int{-1, 1} {
+			if f := file + df; f >= 0 && f < 8 && pos.board[row*8+f] == pawn {
+				key ^= polyglotRandom[polyglotEnPassantOffset+file]
+				break
+			}
+		}
+	}
+	if pos.whiteToMove {
+		key ^= polyglotRandom[polyglotTurnOffset]
+	}
+	return key, nil
+}
+
+// bookEntry is one 16 byte record of a Polyglot book.
+type bookEntry struct {
+	key    uint64
+	move   uint16
+	weight uint16
+}
+
+// openingBook is a Polyglot book held in memory, sorted by key.
+type openingBook struct {
+	entries []bookEntry
+}
+
+var errBadBook = errors.New("invalid polyglot book")
+
+// loadPolyglotBook parses the big-endian Polyglot .bin format.
+func loadPolyglotBook(data []byte) (*openingBook, error) {
+	if len(data) == 0 || len(data)%polyglotEntrySize != 0 {
+		return nil, errBadBook
+	}
+	book := &openingBook{entries: make([]bookEntry, 0, len(data)/polyglotEntrySize)}
+	for off := 0; off < len(data); off += polyglotEntrySize {
+		rec := data[off : off+polyglotEntrySize]
+		book.entries = append(book.entries, bookEntry{
+			key:    binary.BigEndian.Uint64(rec[0:8]),
+			move:   binary.BigEndian.Uint16(rec[8:10]),
+			weight: binary.BigEndian.Uint16(rec[10:12]),
+		})
+	}
+	// books are sorted already, but don't rely on it for user supplied files
+	sort.SliceStable(book.entries, func(i, j int) bool { return book.entries[i].key < book.entries[j].key })
+	return book, nil
+}
+
+// lookup returns all entries stored for key.
+func (b *openingBook) lookup(key uint64) []bookEntry {
+	i := sort.Search(len(b.entries), func(i int) bool { return b.entries[i].key >= key })
+	j := i
+	for j < len(b.entries) && b.entries[j].key == key {
+		j++
+	}
+	return b.entries[i:j]
+}
+
+// pickMove chooses a legal book move for g at random, weighted by the entry weights.
+func (b *openingBook) pickMove(g *engine.Game) (engine.Move, bool) {
+	key, err := polyglotKey(g.ToFEN())
+	if err != nil {
+		return engine.Move{}, false
+	}
+	legal := g.GetAllLegalMoves()
+	var candidates []engine.Move
+	var weights []int
+	total := 0
+	for _, e := range b.lookup(key) {
+		mv, ok := decodeBookMove(e.move, legal)
+		if !ok || e.weight == 0 {
+			continue
+		}
+		candidates = append(candidates, mv)
+		weights = append(weights, int(e.weight))
+		total += int(e.weight)
+	}
+	if total == 0 {
+		return engine.Move{}, false
+	}
+	r := rand.Intn(total)
+	for i, w := range weights {
+		if r < w {
+			return candidates[i], true
+		}
+		r -= w
+	}
+	return candidates[len(candidates)-1], true
+}
+
+// decodeBookMove maps a Polyglot move to one of the legal moves. Castling is
+// stored as king-takes-own-rook (e1h1) and is converted to the king's target square.
+func decodeBookMove(m uint16, legal []engine.Move) (engine.Move, bool) {
+	toFile, toRow := int(m&7), int(m>>3&7)
+	fromFile, fromRow := int(m>>6&7), int(m>>9&7)
+	promo := int(m >> 12 & 7)
+	from := engine.Square(fromRow*8 + fromFile)
+	to := engine.Square(toRow*8 + toFile)
+	for _, mv := range legal {
+		if mv.From != from {
+			continue
+		}
+		if mv.Piece.Type == engine.King && fromFile == 4 && fromRow == toRow && (toFile == 0 || toFile == 7) {
+			castleTo := engine.Square(toRow*8 + 6)
+			if toFile == 0 {
+				castleTo = engine.Square(toRow*8 + 2)
+			}
+			if mv.To == castleTo {
+				return mv, true
+			}
+			continue
+		}
+		if mv.To != to {
+			continue
+		}
+		if promo != 0 && mv.Promotion != polyglotPromotion(promo) {
+			continue
+		}
+		return mv, true
+	}
+	return engine.Move{}, false
+}
+
+// polyglotPromotion decodes the promotion field of a book move (1=N ... 4=Q).
+func polyglotPromotion(p int) engine.PieceType {
+	switch p {
+	case 1:
+		return engine.Knight
+	case 2:
+		return engine.Bishop
+	case 3:
+		return engine.Rook
+	}
+	return engine.Queen
+}
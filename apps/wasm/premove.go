@@ -0,0 +1,155 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"go.rumenx.com/chess/engine"
+)
+
+var premoveColor = color.RGBA{0x99, 0x4C, 0xC8, 0x66}
+
+// premove is a move queued while the AI thinks. It is only checked for the
+// piece's pattern when queued and played if legal once it is the player's
+// turn.
+type premove struct {
+	from, to engine.Square
+}
+
+// aiToMove reports whether the AI is to move in the live game, when clicks
+// on the board queue premoves.
+func (u *uiGame) aiToMove() bool {
+	return !u.training() && u.g.ActiveColor() == u.aiColor()
+}
+
+// premoveBoard returns the live board with the queued premoves made, so
+// later premoves can start where earlier ones end.
+func (u *uiGame) premoveBoard() [64]engine.Piece {
+	var board [64]engine.Piece
+	live := u.g.Board()
+	for sq := engine.Square(0); sq < 64; sq++ {
+		board[sq] = live.GetPiece(sq)
+	}
+	for _, pm := range u.premoves {
+		board[pm.to], board[pm.from] = board[pm.from], engine.Piece{}
+	}
+	return board
+}
+
+// clickPremove selects a piece of the player's and queues its move on the
+// next click, like a normal move.
+func (u *uiGame) clickPremove(sq engine.Square) {
+	board := u.premoveBoard()
+	p := board[sq]
+	own := !p.IsEmpty() && p.Color == u.playerColor
+	switch {
+	case u.premoveFrom != nil && *u.premoveFrom == sq:
+		u.premoveFrom = nil
+	case own && (u.premoveFrom == nil || board[*u.premoveFrom].Type != engine.King || p.Type != engine.Rook):
+		u.premoveFrom = &sq // another of the player's pieces; a king clicked onto its rook castles
+	case u.premoveFrom != nil:
+		from := *u.premoveFrom
+		u.premoveFrom = nil
+		if premoveReachable(board[from], from, sq) {
+			u.premoves = append(u.premoves, premove{from, sq})
+			u.flashMsg(trf("Premove %s-%s", from, sq))
+		}
+	}
+}
+
+// premoveReachable reports whether piece p could move from one square to
+// the other on some board: the pattern is checked, not the position.
+func premoveReachable(p engine.Piece, from, to engine.Square) bool {
+	if p.IsEmpty() || from == to {
+		return false
+	}
+	df, dr := to.File()-from.File(), to.Rank()-from.Rank()
+	adf, adr := absInt(df), absInt(dr)
+	line := (adf == 0) != (adr == 0)
+	diagonal := adf == adr
+	switch p.Type {
+	case engine.Knight:
+		return adf*adr == 2
+	case engine.Bishop:
+		return diagonal
+	case engine.Rook:
+		return line
+	case engine.Queen:
+		return line || diagonal
+	case engine.King:
+		backRank := 0
+		if p.Color == engine.Black {
+			backRank = 7
+		}
+		// castling, also onto a Chess960 rook
+		return max(adf, adr) == 1 || (adr == 0 && from.Rank() == backRank)
+	case engine.Pawn:
+		forward, start := 1, 1
+		if p.Color == engine.Black {
+			forward, start = -1, 6
+		}
+		return (df == 0 && (dr == forward || (dr == 2*forward && from.Rank() == start))) || (adf == 1 && dr == forward)
+	}
+	return false
+}
+
+// playPremove plays the first queued premove as soon as it is the
+// player's turn. A premove that is illegal then cancels the queue; a
+// promotion queens, or opens the picker when auto-queen is off.
+func (u *uiGame) playPremove() {
+	if len(u.premoves) == 0 || u.aiPending || u.aiToMove() {
+		return
+	}
+	pm := u.premoves[0]
+	u.premoves = u.premoves[1:]
+	for _, mv := range u.allLegalMoves() {
+		if mv.From != pm.from {
+			continue
+		}
+		to := mv.To
+		if mv.Type == engine.Castling {
+			if rook, ok := castlingRookSquare(u.g, mv); ok && rook == pm.to {
+				to = rook
+			}
+		}
+		if to == pm.to {
+			u.leaveView()
+			u.selected = nil
+			u.legalTargets = map[engine.Square]bool{}
+			if isPromotion(mv) && !u.autoQueen {
+				u.askPromotion(mv)
+				return
+			}
+			u.applyMove(autoQueen(mv))
+			return
+		}
+	}
+	u.premoves = nil
+	if len(u.allLegalMoves()) > 0 {
+		u.flashMsg(trf("Premove %s-%s is illegal, premoves cancelled", pm.from, pm.to))
+		u.playSound(soundIllegal)
+	}
+}
+
+// cancelPremoves drops the queued premoves and the premove selection; it
+// reports whether there was anything to drop.
+func (u *uiGame) cancelPremoves() bool {
+	if len(u.premoves) == 0 && u.premoveFrom == nil {
+		return false
+	}
+	u.premoves, u.premoveFrom = nil, nil
+	u.flashMsg(tr("Premoves cancelled"))
+	return true
+}
+
+// drawPremoves highlights the squares of the queued premoves and the
+// selected premove piece.
+func (u *uiGame) drawPremoves(board *ebiten.Image) {
+	for _, pm := range u.premoves {
+		u.highlightSquare(board, pm.from, premoveColor)
+		u.highlightSquare(board, pm.to, premoveColor)
+	}
+	if u.premoveFrom != nil {
+		u.highlightSquare(board, *u.premoveFrom, premoveColor)
+	}
+}
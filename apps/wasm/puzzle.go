@@ -0,0 +1,307 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.rumenx.com/chess/engine"
+)
+
+// puzzlesTSV holds mate-in-1 and mate-in-2 puzzles found in rated lichess
+// games (CC0): id (game id and ply), rating, FEN, UCI solution, theme.
+//
+//go:embed puzzles.tsv
+var puzzlesTSV string
+
+const (
+	puzzleStartRating = 1200
+	puzzleK           = 32 // Elo K-factor for the puzzle rating
+	puzzleReplyDelay  = 400 * time.Millisecond
+	puzzleSettingKey  = "puzzles.json"
+)
+
+// puzzle is a position with its solution line: the solver's moves
+// alternating with the forced replies.
+type puzzle struct {
+	id     string
+	rating int
+	fen    string
+	moves  []string // UCI
+	themes string
+	setup  bool // moves[0] is the opponent's move leading to the puzzle (lichess format)
+}
+
+// puzzleProgress is the persisted puzzle state.
+type puzzleProgress struct {
+	Rating     int      `json:"rating"`
+	Streak     int      `json:"streak"`
+	BestStreak int      `json:"bestStreak"`
+	Solved     []string `json:"solved"`
+}
+
+// puzzleSession is the puzzle being played in puzzle mode.
+type puzzleSession struct {
+	set      []puzzle
+	progress puzzleProgress
+	cur      *puzzle
+	step     int  // index of the next move in cur.moves
+	tried    bool // the user has moved in the current puzzle
+	failed   bool // a wrong move was played; the puzzle counts as lost
+	solved   bool
+	replyAt  time.Time
+}
+
+// parsePuzzles reads the embedded TSV format or the lichess puzzle CSV
+// (PuzzleId,FEN,Moves,Rating,...,Themes). Lichess FENs are taken before the
+// opponent's move, which is played automatically when the puzzle starts.
+func parsePuzzles(data string) []puzzle {
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+	lichess := len(lines) > 0 && strings.HasPrefix(lines[0], "PuzzleId")
+	var out []puzzle
+	for _, line := range lines[1:] {
+		sep := "\t"
+		if lichess {
+			sep = ","
+		}
+		cols := strings.Split(line, sep)
+		var p puzzle
+		switch {
+		case lichess && len(cols) >= 8:
+			p = puzzle{id: cols[0], fen: cols[1], moves: strings.Fields(cols[2]), themes: cols[7], setup: true}
+			p.rating, _ = strconv.Atoi(cols[3])
+		case !lichess && len(cols) >= 4:
+			p = puzzle{id: cols[0], fen: cols[2], moves: strings.Fields(cols[3])}
+			p.rating, _ = strconv.Atoi(cols[1])
+			if len(cols) > 4 {
+				p.themes = cols[4]
+			}
+		default:
+			continue
+		}
+		if len(p.moves) > 0 {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// loadPuzzles reads the file named by CHESS_PUZZLES, falling back to the embedded set.
+func loadPuzzles() []puzzle {
+	if path := os.Getenv("CHESS_PUZZLES"); path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			if set := parsePuzzles(string(data)); len(set) > 0 {
+				log.Printf("[DEBUG] Loaded %d puzzles from %s", len(set), path)
+				return set
+			}
+		}
+		log.Printf("[ERROR] Could not load puzzles from %s, using embedded set", path)
+	}
+	return parsePuzzles(puzzlesTSV)
+}
+
+func loadPuzzleProgress() puzzleProgress {
+	p := puzzleProgress{Rating: puzzleStartRating}
+	if data, ok := loadSetting(puzzleSettingKey); ok {
+		_ = json.Unmarshal([]byte(data), &p)
+	}
+	return p
+}
+
+func (s *puzzleSession) save() {
+	data, err := json.Marshal(s.progress)
+	if err == nil {
+		err = saveSetting(puzzleSettingKey, string(data))
+	}
+	if err != nil {
+		log.Printf("[ERROR] Saving puzzle progress: %v", err)
+	}
+}
+
+// next picks an unsolved puzzle near the player's rating.
+func (s *puzzleSession) next() *puzzle {
+	solved := map[string]bool{}
+	for _, id := range s.progress.Solved {
+		solved[id] = true
+	}
+	var candidates []*puzzle
+	for window := 100; len(candidates) == 0 && window <= 3200; window *= 2 {
+		for i := range s.set {
+			p := &s.set[i]
+			if !solved[p.id] && absInt(p.rating-s.progress.Rating) <= window {
+				candidates = append(candidates, p)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		// everything solved: start over with the whole set
+		for i := range s.set {
+			candidates = append(candidates, &s.set[i])
+		}
+	}
+	return candidates[rand.Intn(len(candidates))]
+}
+
+// finish records the outcome of the current puzzle once.
+func (s *puzzleSession) finish(won bool) int {
+	expected := 1 / (1 + math.Pow(10, float64(s.cur.rating-s.progress.Rating)/400))
+	score := 0.0
+	if won {
+		score = 1
+	}
+	delta := int(math.Round(puzzleK * (score - expected)))
+	s.progress.Rating += delta
+	if won {
+		s.progress.Streak++
+		if s.progress.Streak > s.progress.BestStreak {
+			s.progress.BestStreak = s.progress.Streak
+		}
+		s.progress.Solved = append(s.progress.Solved, s.cur.id)
+	} else {
+		s.progress.Streak = 0
+	}
+	s.save()
+	return delta
+}
+
+// moveUCI formats mv in UCI notation (e2e4, e7e8q).
+func moveUCI(mv engine.Move) string {
+	s := mv.From.String() + mv.To.String()
+	if mv.Type == engine.Promotion {
+		s += strings.ToLower(string(pieceLetter(mv.Promotion)))
+	}
+	return s
+}
+
+// findUCIMove returns the legal move of g written as uci.
+func findUCIMove(g *engine.Game, uci string) (engine.Move, bool) {
+	for _, mv := range g.GetAllLegalMoves() {
+		if moveUCI(mv) == uci {
+			return mv, true
+		}
+	}
+	return engine.Move{}, false
+}
+
+// togglePuzzles enters or leaves puzzle mode.
+func (u *uiGame) togglePuzzles() {
+	if u.puzzle != nil {
+		u.puzzle = nil
+		u.startFEN = ""
+		u.resetGame(u.playerColor)
+		u.flashMsg(tr("Puzzle mode off"))
+		return
+	}
+	set := loadPuzzles()
+	if len(set) == 0 {
+		u.flashMsg(tr("No puzzles available"))
+		return
+	}
+	u.puzzle = &puzzleSession{set: set, progress: loadPuzzleProgress()}
+	u.chess960, u.odds, u.drill, u.endgame = false, oddsNone, nil, nil
+	u.nextPuzzle()
+}
+
+// nextPuzzle sets up a new puzzle; abandoning an unsolved one counts as a loss.
+func (u *uiGame) nextPuzzle() {
+	s := u.puzzle
+	if s.cur != nil && s.tried && !s.solved && !s.failed {
+		s.finish(false)
+	}
+	s.cur, s.step, s.tried, s.failed, s.solved = s.next(), 0, false, false, false
+	s.replyAt = time.Time{}
+	u.startFEN = s.cur.fen
+	u.resetGame(u.playerColor)
+	if s.cur.setup {
+		if mv, ok := findUCIMove(u.g, s.cur.moves[0]); ok && u.g.MakeMove(mv) == nil {
+			u.lastMove = &mv
+			u.movesSAN = u.g.GenerateSAN()
+			s.step = 1
+		}
+	}
+	u.playerColor = u.g.ActiveColor()
+	u.whiteAtBottom = u.playerColor == engine.White
+	u.flashMsg(trf("Puzzle: find the best move for %s", tr(u.playerColor.String())))
+}
+
+// puzzleMoved checks a move the user just played against the solution.
+// Any checkmate is accepted even if the line continues differently.
+func (u *uiGame) puzzleMoved(mv engine.Move) {
+	s := u.puzzle
+	if s.solved || s.step >= len(s.cur.moves) {
+		return
+	}
+	s.tried = true
+	mate := len(u.g.GetAllLegalMoves()) == 0 && inCheck(u.g)
+	if moveUCI(mv) != s.cur.moves[s.step] && !mate {
+		_, _ = u.g.UndoMove()
+		u.movesSAN = u.g.GenerateSAN()
+		u.lastMove = nil
+		u.playSound(soundIllegal)
+		if !s.failed {
+			s.failed = true
+			delta := s.finish(false)
+			u.flashMsg(trf("Wrong move (%d), try again", delta))
+		} else {
+			u.flashMsg(tr("Wrong move, try again"))
+		}
+		return
+	}
+	s.step++
+	if mate || s.step >= len(s.cur.moves) {
+		s.solved = true
+		if s.failed {
+			u.flashMsg(tr("Solved (not counted). N = next puzzle"))
+			return
+		}
+		u.flashMsg(trf("Solved! +%d. N = next puzzle", s.finish(true)))
+		return
+	}
+	s.replyAt = time.Now().Add(puzzleReplyDelay)
+}
+
+// updatePuzzle plays the forced reply from the solution line after a short pause.
+func (u *uiGame) updatePuzzle() {
+	s := u.puzzle
+	if s == nil || s.solved || s.replyAt.IsZero() || time.Now().Before(s.replyAt) {
+		return
+	}
+	s.replyAt = time.Time{}
+	if s.step >= len(s.cur.moves) || u.g.ActiveColor() == u.playerColor {
+		return
+	}
+	mv, ok := findUCIMove(u.g, s.cur.moves[s.step])
+	if !ok || u.g.MakeMove(mv) != nil {
+		log.Printf("[ERROR] Puzzle %s: illegal solution move %s", s.cur.id, s.cur.moves[s.step])
+		return
+	}
+	u.lastMove = &mv
+	u.movesSAN = u.g.GenerateSAN()
+	s.step++
+}
+
+// puzzleLines returns the panel lines for puzzle mode.
+func (u *uiGame) puzzleLines() []string {
+	s := u.puzzle
+	if s == nil || s.cur == nil {
+		return nil
+	}
+	state := tr("Your move")
+	switch {
+	case s.solved:
+		state = tr("Solved")
+	case s.failed:
+		state = tr("Failed, keep trying")
+	}
+	return []string{
+		trf("Puzzle %s (%d)", s.cur.id, s.cur.rating),
+		trf("Rating %d streak %d best %d", s.progress.Rating, s.progress.Streak, s.progress.BestStreak),
+		state,
+	}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"go.rumenx.com/chess/engine"
+)
+
+// sampleRepertoire is used when no repertoire file is configured.
+//
+//go:embed repertoire.pgn
+var sampleRepertoire string
+
+const (
+	drillReplyDelay = 400 * time.Millisecond
+	drillSettingKey = "repertoire.json"
+)
+
+// drillIntervals are the spaced repetition intervals per Leitner box; a
+// mistake sends a move back to box 0.
+var drillIntervals = []time.Duration{0, 24 * time.Hour, 3 * 24 * time.Hour, 7 * 24 * time.Hour, 14 * 24 * time.Hour, 30 * 24 * time.Hour, 60 * 24 * time.Hour}
+
+// drillCard is the review state of one prepared move, keyed by its line.
+type drillCard struct {
+	Box int   `json:"box"`
+	Due int64 `json:"due"` // unix seconds
+}
+
+// drillSession drills a repertoire tree from the player's side.
+type drillSession struct {
+	root    *pgnNode
+	cards   map[string]*drillCard
+	node    *pgnNode // last move played in the tree
+	missed  bool     // the current move was answered wrongly
+	done    bool     // end of the line reached
+	replyAt time.Time
+}
+
+// loadRepertoire reads the PGN named by CHESS_REPERTOIRE (all games and
+// variations merged into one tree), falling back to the sample repertoire.
+func loadRepertoire() *pgnNode {
+	text := sampleRepertoire
+	if path := os.Getenv("CHESS_REPERTOIRE"); path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			text = string(data)
+		} else {
+			log.Printf("[ERROR] Could not read repertoire %s: %v", path, err)
+		}
+	}
+	games, err := parsePGN(text)
+	if err != nil {
+		log.Printf("[ERROR] Could not parse repertoire: %v", err)
+		return nil
+	}
+	root := &pgnNode{}
+	var merge func(dst, src *pgnNode)
+	merge = func(dst, src *pgnNode) {
+		for _, c := range src.children {
+			merge(dst.add(c.san), c)
+		}
+	}
+	for _, g := range games {
+		merge(root, g.root)
+	}
+	if len(root.children) == 0 {
+		return nil
+	}
+	return root
+}
+
+func drillKey(n *pgnNode) string { return strings.Join(n.line(), " ") }
+
+// userToMove reports whether the player moves after node n (the root is
+// before White's first move).
+func (u *uiGame) userToMove(n *pgnNode) bool {
+	white := len(n.line())%2 == 0
+	return white == (u.playerColor == engine.White)
+}
+
+func (s *drillSession) card(n *pgnNode) *drillCard {
+	key := drillKey(n)
+	c := s.cards[key]
+	if c == nil {
+		c = &drillCard{}
+		s.cards[key] = c
+	}
+	return c
+}
+
+func (s *drillSession) save() {
+	data, err := json.Marshal(s.cards)
+	if err == nil {
+		err = saveSetting(drillSettingKey, string(data))
+	}
+	if err != nil {
+		log.Printf("[ERROR] Saving repertoire progress: %v", err)
+	}
+}
+
+// review moves a card up one box, or back to box 0 after a mistake.
+func (s *drillSession) review(n *pgnNode, correct bool) {
+	c := s.card(n)
+	if correct {
+		if c.Box < len(drillIntervals)-1 {
+			c.Box++
+		}
+	} else {
+		c.Box = 0
+	}
+	c.Due = time.Now().Add(drillIntervals[c.Box]).Unix()
+	s.save()
+}
+
+// nextDue returns the earliest due time of the player's moves below n.
+func (u *uiGame) nextDue(n *pgnNode) int64 {
+	best := int64(1) << 62
+	for _, c := range n.children {
+		if u.userToMove(n) {
+			if card := u.drill.cards[drillKey(c)]; card == nil {
+				best = 0 // never reviewed
+			} else if card.Due < best {
+				best = card.Due
+			}
+		}
+		if d := u.nextDue(c); d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+// toggleDrill enters or leaves repertoire drill mode.
+func (u *uiGame) toggleDrill() {
+	if u.drill != nil {
+		u.drill = nil
+		u.resetGame(u.playerColor)
+		u.flashMsg(tr("Repertoire drill off"))
+		return
+	}
+	root := loadRepertoire()
+	if root == nil {
+		u.flashMsg(tr("No repertoire loaded"))
+		return
+	}
+	s := &drillSession{root: root, cards: map[string]*drillCard{}}
+	if data, ok := loadSetting(drillSettingKey); ok {
+		_ = json.Unmarshal([]byte(data), &s.cards)
+	}
+	u.puzzle, u.endgame, u.chess960, u.odds = nil, nil, false, oddsNone
+	u.startFEN = ""
+	u.drill = s
+	u.nextDrillLine()
+}
+
+// nextDrillLine restarts from the initial position; the GUI then steers
+// towards the moves that are due first.
+func (u *uiGame) nextDrillLine() {
+	s := u.drill
+	u.resetGame(u.playerColor)
+	s.node, s.missed, s.done = s.root, false, false
+	s.replyAt = time.Now()
+	u.flashMsg(trf("Drill: play your prepared moves as %s", tr(u.playerColor.String())))
+}
+
+// drillMoved checks the player's move against the repertoire.
+func (u *uiGame) drillMoved() {
+	s := u.drill
+	if s.done || !u.userToMove(s.node) || len(s.node.children) == 0 {
+		return
+	}
+	sans := u.g.GenerateSAN()
+	played := sans[len(sans)-1]
+	next := s.node.child(played)
+	if next == nil {
+		_, _ = u.g.UndoMove()
+		u.movesSAN = u.g.GenerateSAN()
+		u.lastMove = nil
+		u.playSound(soundIllegal)
+		var expected []string
+		for _, c := range s.node.children {
+			expected = append(expected, localizeSAN(c.san))
+			if !s.missed {
+				s.review(c, false)
+			}
+		}
+		s.missed = true
+		if mv, ok := sanMove(u.g, s.node.children[0].san); ok {
+			u.hintMove = &mv
+			u.hintPly = u.ply()
+		}
+		u.flashMsg(trf("%s is not in your repertoire: %s", localizeSAN(played), strings.Join(expected, ", ")))
+		return
+	}
+	if !s.missed {
+		s.review(next, true)
+	}
+	s.node, s.missed = next, false
+	if len(next.children) == 0 {
+		s.done = true
+		u.flashMsg(tr("Line complete. N = next line"))
+		return
+	}
+	s.replyAt = time.Now().Add(drillReplyDelay)
+}
+
+// updateDrill plays the opponent's repertoire move, preferring the branch
+// with the most overdue moves.
+func (u *uiGame) updateDrill() {
+	s := u.drill
+	if s == nil || s.done || s.replyAt.IsZero() || time.Now().Before(s.replyAt) {
+		return
+	}
+	s.replyAt = time.Time{}
+	if u.userToMove(s.node) {
+		return
+	}
+	if len(s.node.children) == 0 {
+		s.done = true
+		u.flashMsg(tr("Line complete. N = next line"))
+		return
+	}
+	var best []*pgnNode
+	bestDue := int64(0)
+	for _, c := range s.node.children {
+		due := u.nextDue(c)
+		switch {
+		case len(best) == 0 || due < bestDue:
+			best, bestDue = []*pgnNode{c}, due
+		case due == bestDue:
+			best = append(best, c)
+		}
+	}
+	next := best[rand.Intn(len(best))]
+	mv, ok := sanMove(u.g, next.san)
+	if !ok || u.g.MakeMove(mv) != nil {
+		s.done = true
+		u.flashMsg(trf("Repertoire move %s is illegal here", localizeSAN(next.san)))
+		return
+	}
+	u.lastMove = &mv
+	u.movesSAN = u.g.GenerateSAN()
+	s.node = next
+	if len(next.children) == 0 {
+		s.done = true
+		u.flashMsg(tr("Line complete. N = next line"))
+	}
+}
+
+// drillLines returns the panel lines for drill mode.
+func (u *uiGame) drillLines() []string {
+	s := u.drill
+	if s == nil {
+		return nil
+	}
+	due := 0
+	now := time.Now().Unix()
+	var count func(n *pgnNode)
+	count = func(n *pgnNode) {
+		for _, c := range n.children {
+			if u.userToMove(n) {
+				if card := s.cards[drillKey(c)]; card == nil || card.Due <= now {
+					due++
+				}
+			}
+			count(c)
+		}
+	}
+	count(s.root)
+	lines := []string{trf("Repertoire drill: %d moves due", due)}
+	if s.node.comment != "" {
+		lines = append(lines, wrapText(s.node.comment, panelTextWidth)...)
+	}
+	return lines
+}
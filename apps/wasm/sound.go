@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// soundFiles are short synthesized cues (22.05 kHz mono WAV).
+//
+//go:embed sounds/*.wav
+var soundFiles embed.FS
+
+type soundKind int
+
+const (
+	soundMove soundKind = iota
+	soundCapture
+	soundCheck
+	soundCastle
+	soundPromote
+	soundGameEnd
+	soundLowTime
+	soundIllegal
+)
+
+var soundNames = [...]string{"move", "capture", "check", "castle", "promote", "gameend", "lowtime", "illegal"}
+
+const (
+	audioSampleRate   = 44100
+	volumeSettingKey  = "volume"
+	defaultVolumeStep = 3
+)
+
+// volumeLevels are the selectable volumes in percent; 0 mutes.
+var volumeLevels = []int{0, 25, 50, 75, 100}
+
+// soundPlayer holds the audio context and the decoded cues.
+type soundPlayer struct {
+	ctx *audio.Context
+	pcm [len(soundNames)][]byte
+}
+
+func newSoundPlayer() *soundPlayer {
+	s := &soundPlayer{ctx: audio.NewContext(audioSampleRate)}
+	for k, name := range soundNames {
+		data, err := soundFiles.ReadFile("sounds/" + name + ".wav")
+		if err != nil {
+			continue
+		}
+		stream, err := wav.DecodeWithSampleRate(audioSampleRate, bytes.NewReader(data))
+		if err == nil {
+			s.pcm[k], err = io.ReadAll(stream)
+		}
+		if err != nil {
+			log.Printf("[ERROR] Decoding sound %s: %v", name, err)
+		}
+	}
+	return s
+}
+
+// loadVolume returns the saved volume, or the default level.
+func loadVolume() int {
+	if v, ok := loadSetting(volumeSettingKey); ok {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 && n <= 100 {
+			return n
+		}
+	}
+	return volumeLevels[defaultVolumeStep]
+}
+
+// playSound plays cue k at the current volume; before audio has started it
+// does nothing.
+func (u *uiGame) playSound(k soundKind) {
+	if u.sound == nil || u.volume == 0 || u.sound.pcm[k] == nil {
+		return
+	}
+	p := u.sound.ctx.NewPlayerFromBytes(u.sound.pcm[k])
+	p.SetVolume(float64(u.volume) / 100)
+	p.Play()
+}
+
+// setVolume selects a volume in percent and saves it.
+func (u *uiGame) setVolume(v int) {
+	u.volume = v
+	if err := saveSetting(volumeSettingKey, strconv.Itoa(v)); err != nil {
+		log.Printf("[ERROR] Saving volume: %v", err)
+	}
+	u.flashMsg(u.volumeLabel())
+}
+
+// cycleVolume selects the next volume level.
+func (u *uiGame) cycleVolume() {
+	next := volumeLevels[0]
+	for _, v := range volumeLevels {
+		if v > u.volume {
+			next = v
+			break
+		}
+	}
+	u.setVolume(next)
+}
+
+// toggleMute silences the cues or restores the volume used before muting.
+func (u *uiGame) toggleMute() {
+	if u.volume > 0 {
+		u.unmuteVolume = u.volume
+		u.setVolume(0)
+		return
+	}
+	if u.unmuteVolume == 0 {
+		u.unmuteVolume = volumeLevels[defaultVolumeStep]
+	}
+	u.setVolume(u.unmuteVolume)
+}
+
+func (u *uiGame) volumeLabel() string {
+	if u.volume == 0 {
+		return tr("Sound: off")
+	}
+	return trf("Sound: %d%%", u.volume)
+}
+
+// updateSound starts audio (in the browser only after the first key press,
+// click or touch, as autoplay rules require) and plays the cue for each new
+// move.
+func (u *uiGame) updateSound() {
+	if u.sound == nil {
+		if audioNeedsGesture && len(inpututil.AppendJustPressedKeys(nil)) == 0 &&
+			!inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && len(inpututil.AppendJustPressedTouchIDs(nil)) == 0 {
+			return
+		}
+		u.sound = newSoundPlayer()
+	}
+	ply := u.ply()
+	if ply == u.soundPly {
+		return
+	}
+	added := ply > u.soundPly
+	u.soundPly = ply
+	if !added || ply > len(u.movesSAN) {
+		return
+	}
+	san := u.movesSAN[ply-1]
+	switch {
+	case len(u.allLegalMoves()) == 0:
+		u.playSound(soundGameEnd)
+	case strings.ContainsAny(san, "+#"):
+		u.playSound(soundCheck)
+	case strings.Contains(san, "="):
+		u.playSound(soundPromote)
+	case strings.HasPrefix(san, "O-O"):
+		u.playSound(soundCastle)
+	case strings.Contains(san, "x"):
+		u.playSound(soundCapture)
+	default:
+		u.playSound(soundMove)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build js && wasm
+
+package main
+
+// audioNeedsGesture delays the audio context until the first user input,
+// since browsers keep audio suspended until then.
+const audioNeedsGesture = true
@@ -0,0 +1,8 @@
+//go:build !(js && wasm)
+
+package main
+
+import "os"
+
+// startupFEN returns the start position given in CHESS_FEN, if any.
+func startupFEN() string { return os.Getenv("CHESS_FEN") }
@@ -0,0 +1,15 @@
+//go:build js && wasm
+
+package main
+
+import "syscall/js"
+
+// startupFEN returns the "fen" query parameter of the page URL, if any.
+func startupFEN() string {
+	search := js.Global().Get("location").Get("search")
+	params := js.Global().Get("URLSearchParams").New(search)
+	if fen := params.Call("get", "fen"); fen.Truthy() {
+		return fen.String()
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// settingPath returns the file holding key in the user's config directory.
+func settingPath(key string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-chess", key), nil
+}
+
+// loadSetting returns the value stored under key.
+func loadSetting(key string) (string, bool) {
+	path, err := settingPath(key)
+	if err != nil {
+		return "", false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// saveSetting stores value under key.
+func saveSetting(key, value string) error {
+	path, err := settingPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(value), 0o644)
+}
@@ -0,0 +1,20 @@
+//go:build js && wasm
+
+package main
+
+import "syscall/js"
+
+// loadSetting returns the value stored under key in the browser's localStorage.
+func loadSetting(key string) (string, bool) {
+	v := js.Global().Get("localStorage").Call("getItem", "go-chess."+key)
+	if v.IsNull() || v.IsUndefined() {
+		return "", false
+	}
+	return v.String(), true
+}
+
+// saveSetting stores value under key in the browser's localStorage.
+func saveSetting(key, value string) error {
+	js.Global().Get("localStorage").Call("setItem", "go-chess."+key, value)
+	return nil
+}
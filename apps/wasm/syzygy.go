@@ -0,0 +1,998 @@
+package main
+
+// Syzygy WDL/DTZ tablebase probing. The table decoder follows the reference
+// implementation used by Stockfish and Fathom; move generation for the small
+// capture searches is delegated to the engine.
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.rumenx.com/chess/engine"
+)
+
+// tbMaxPieces is the largest table size the decoder supports.
+const tbMaxPieces = 7
+
+// Table flags stored in the pairs header.
+const (
+	tbFlagSTM         = 1
+	tbFlagMapped      = 2
+	tbFlagWinPlies    = 4
+	tbFlagLossPlies   = 8
+	tbFlagWide        = 16
+	tbFlagSingleValue = 128
+)
+
+// WDL scores from the side to move's point of view.
+const (
+	wdlLoss        = -2
+	wdlBlessedLoss = -1
+	wdlDraw        = 0
+	wdlCursedWin   = 1
+	wdlWin         = 2
+)
+
+// probe states, as in the reference implementation
+const (
+	tbFail            = 0
+	tbOK              = 1
+	tbChangeSTM       = -1
+	tbZeroingBestMove = 2
+)
+
+var (
+	wdlMagic = []byte{0xD7, 0x66, 0x0C, 0xA5}
+	dtzMagic = []byte{0x71, 0xE8, 0x23, 0x5D}
+
+	errBadTable = errors.New("invalid syzygy table")
+)
+
+// Encoding tables, filled by initTablebaseMaps.
+var (
+	tbMapB1H1H7     [64]int
+	tbMapA1D1D4     [64]int
+	tbMapKK         [10][64]int
+	tbBinomial      [tbMaxPieces][64]uint64
+	tbMapPawns      [64]int
+	tbLeadPawnIdx   [tbMaxPieces][64]uint64
+	tbLeadPawnsSize [tbMaxPieces][4]uint64
+)
+
+func init() { initTablebaseMaps() }
+
+func offA1H8(s int) int { return s>>3 - s&7 }
+
+func initTablebaseMaps() {
+	code := 0
+	for s := 0; s < 64; s++ {
+		if offA1H8(s) < 0 {
+			tbMapB1H1H7[s] = code
+			code++
+		}
+	}
+
+	var diagonal []int
+	code = 0
+	for s := 0; s <= 27; s++ { // a1..d4
+		if offA1H8(s) < 0 && s&7 <= 3 {
+			tbMapA1D1D4[s] = code
+			code++
+		} else if offA1H8(s) == 0 && s&7 <= 3 {
+			diagonal = append(diagonal, s)
+		}
+	}
+	for _, s := range diagonal {
+		tbMapA1D1D4[s] = code
+		code++
+	}
+
+	type kk struct{ idx, sq int }
+	var bothOnDiagonal []kk
+	code = 0
+	for idx := 0; idx < 10; idx++ {
+		for s1 := 0; s1 <= 27; s1++ {
+			if tbMapA1D1D4[s1] != idx || (idx == 0 && s1 != 1) { // b1 is mapped to 0
+				continue
+			}
+			for s2 := 0; s2 < 64; s2++ {
+				switch {
+				case kingDistance(s1, s2) <= 1:
+					// illegal: kings adjacent or on the same square
+				case offA1H8(s1) == 0 && offA1H8(s2) > 0:
+					// first on the diagonal, second above it
+				case offA1H8(s1) == 0 && offA1H8(s2) == 0:
+					bothOnDiagonal = append(bothOnDiagonal, kk{idx, s2})
+				default:
+					tbMapKK[idx][s2] = code
+					code++
+				}
+			}
+		}
+	}
+	for _, p := range bothOnDiagonal {
+		tbMapKK[p.idx][p.sq] = code
+		code++
+	}
+
+	tbBinomial[0][0] = 1
+	for n := 1; n < 64; n++ {
+		for k := 0; k < tbMaxPieces && k <= n; k++ {
+			var v uint64
+			if k > 0 {
+				v += tbBinomial[k-1][n-1]
+			}
+			if k < n {
+				v += tbBinomial[k][n-1]
+			}
+			tbBinomial[k][n] = v
+		}
+	}
+
+	available := 47
+	for lead := 1; lead < tbMaxPieces; lead++ {
+		for f := 0; f < 4; f++ {
+			var idx uint64
+			for r := 1; r <= 6; r++ {
+				sq := r*8 + f
+				if lead == 1 {
+					tbMapPawns[sq] = available
+					available--
+					tbMapPawns[sq^7] = available
+					available--
+				}
+				tbLeadPawnIdx[lead][sq] = idx
+				idx += tbBinomial[lead-1][tbMapPawns[sq]]
+			}
+			tbLeadPawnsSize[lead][f] = idx
+		}
+	}
+}
+
+func kingDistance(a, b int) int {
+	df := absInt(a&7 - b&7)
+	dr := absInt(a>>3 - b>>3)
+	if df > dr {
+		return df
+	}
+	return dr
+}
+
+// tbPairs is the decoding state of one sub-table (side to move and pawn file).
+type tbPairs struct {
+	flags           byte
+	blockSize       int
+	span            int
+	numBlocks       int
+	maxSymLen       int
+	minSymLen       int
+	lowestSym       int // offsets into tbTable.data
+	btree           int
+	blockLength     int
+	blockLengthSize int
+	sparseIndex     int
+	sparseIndexSize int
+	blocks          int
+	base64          []uint64
+	symlen          []byte
+	pieces          [tbMaxPieces]byte
+	groupIdx        [tbMaxPieces + 1]uint64
+	groupLen        [tbMaxPieces + 1]int
+	mapIdx          [4]int
+}
+
+// tbTable is one loaded .rtbw or .rtbz file.
+type tbTable struct {
+	data       []byte
+	dtz        bool
+	key, key2  string // material keys with the stronger side as White, and mirrored
+	pieceCount int
+	hasPawns   bool
+	hasUnique  bool
+	pawnCount  [2]int
+	pairs      [2][4]*tbPairs
+	dtzMap     int
+}
+
+func (t *tbTable) get(stm, file int) *tbPairs {
+	sides := 2
+	if t.dtz {
+		sides = 1
+	}
+	if !t.hasPawns {
+		file = 0
+	}
+	return t.pairs[stm%sides][file]
+}
+
+func (t *tbTable) u16(off int) int { return int(binary.LittleEndian.Uint16(t.data[off:])) }
+
+// newTBTable parses a table file; code is its material name such as "KRvK".
+func newTBTable(code string, data []byte, dtz bool) (*tbTable, error) {
+	magic := wdlMagic
+	if dtz {
+		magic = dtzMagic
+	}
+	if len(data) < 16 || string(data[:4]) != string(magic) {
+		return nil, errBadTable
+	}
+	sides := strings.SplitN(code, "v", 2)
+	if len(sides) != 2 {
+		return nil, errBadTable
+	}
+	t := &tbTable{data: data, dtz: dtz, key: code, key2: sides[1] + "v" + sides[0]}
+	t.pieceCount = len(sides[0]) + len(sides[1])
+	if t.pieceCount > tbMaxPieces {
+		return nil, errBadTable
+	}
+	wp, bp := strings.Count(sides[0], "P"), strings.Count(sides[1], "P")
+	t.hasPawns = wp+bp > 0
+	for _, side := range sides {
+		for _, pc := range "QRBNP" {
+			if strings.Count(side, string(pc)) == 1 {
+				t.hasUnique = true
+			}
+		}
+	}
+	// the leading colour is the side with fewer (but some) pawns
+	if bp == 0 || (wp > 0 && bp >= wp) {
+		t.pawnCount = [2]int{wp, bp}
+	} else {
+		t.pawnCount = [2]int{bp, wp}
+	}
+	if err := t.setup(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// setup reads the table header, mirroring the layout of the reference decoder.
+func (t *tbTable) setup() (err error) {
+	defer func() {
+		if recover() != nil {
+			err = errBadTable // truncated or corrupt file
+		}
+	}()
+	data := t.data
+	off := 4
+	const split, hasPawns = 1, 2
+	if (data[off]&hasPawns != 0) != t.hasPawns || (data[off]&split != 0) != (t.key != t.key2) {
+		return errBadTable
+	}
+	off++
+
+	sides := 1
+	if !t.dtz && t.key != t.key2 {
+		sides = 2
+	}
+	maxFile := 0
+	if t.hasPawns {
+		maxFile = 3
+	}
+	pp := t.hasPawns && t.pawnCount[1] > 0
+
+	for f := 0; f <= maxFile; f++ {
+		for i := 0; i < sides; i++ {
+			t.pairs[i][f] = &tbPairs{}
+		}
+		order := [2][2]int{{int(data[off] & 0xF), 0xF}, {int(data[off] >> 4), 0xF}}
+		if pp {
+			order[0][1] = int(data[off+1] & 0xF)
+			order[1][1] = int(data[off+1] >> 4)
+			off++
+		}
+		off++
+		for k := 0; k < t.pieceCount; k, off = k+1, off+1 {
+			for i := 0; i < sides; i++ {
+				if i == 0 {
+					t.pairs[i][f].pieces[k] = data[off] & 0xF
+				} else {
+					t.pairs[i][f].pieces[k] = data[off] >> 4
+				}
+			}
+		}
+		for i := 0; i < sides; i++ {
+			t.setGroups(t.pairs[i][f], order[i], f)
+		}
+	}
+	off += off & 1
+
+	for f := 0; f <= maxFile; f++ {
+		for i := 0; i < sides; i++ {
+			off = t.setSizes(t.pairs[i][f], off)
+		}
+	}
+	if t.dtz {
+		off = t.setDTZMap(off, maxFile)
+	}
+	for f := 0; f <= maxFile; f++ {
+		for i := 0; i < sides; i++ {
+			d := t.pairs[i][f]
+			d.sparseIndex = off
+			off += d.sparseIndexSize * 6
+		}
+	}
+	for f := 0; f <= maxFile; f++ {
+		for i := 0; i < sides; i++ {
+			d := t.pairs[i][f]
+			d.blockLength = off
+			off += d.blockLengthSize * 2
+		}
+	}
+	for f := 0; f <= maxFile; f++ {
+		for i := 0; i < sides; i++ {
+			d := t.pairs[i][f]
+			off = (off + 0x3F) &^ 0x3F
+			d.blocks = off
+			off += d.numBlocks * d.blockSize
+		}
+	}
+	if off > len(data) {
+		return errBadTable
+	}
+	return nil
+}
+
+// setGroups splits the piece sequence into encoding groups and computes
+// the index multiplier of each group.
+func (t *tbTable) setGroups(d *tbPairs, order [2]int, f int) {
+	n := 0
+	firstLen := 2
+	if t.hasPawns {
+		firstLen = 0
+	} else if t.hasUnique {
+		firstLen = 3
+	}
+	d.groupLen[n] = 1
+	for i := 1; i < t.pieceCount; i++ {
+		firstLen--
+		if firstLen > 0 || d.pieces[i] == d.pieces[i-1] {
+			d.groupLen[n]++
+		} else {
+			n++
+			d.groupLen[n] = 1
+		}
+	}
+	n++
+	d.groupLen[n] = 0
+
+	pp := t.hasPawns && t.pawnCount[1] > 0
+	next := 1
+	if pp {
+		next = 2
+	}
+	freeSquares := 64 - d.groupLen[0]
+	if pp {
+		freeSquares -= d.groupLen[1]
+	}
+	idx := uint64(1)
+	for k := 0; next < n || k == order[0] || k == order[1]; k++ {
+		switch {
+		case k == order[0]: // leading pawns or pieces
+			d.groupIdx[0] = idx
+			switch {
+			case t.hasPawns:
+				idx *= tbLeadPawnsSize[d.groupLen[0]][f]
+			case t.hasUnique:
+				idx *= 31332
+			default:
+				idx *= 462
+			}
+		case k == order[1]: // remaining pawns
+			d.groupIdx[1] = idx
+			idx *= tbBinomial[d.groupLen[1]][48-d.groupLen[0]]
+		default: // remaining pieces
+			d.groupIdx[next] = idx
+			idx *= tbBinomial[d.groupLen[next]][freeSquares]
+			freeSquares -= d.groupLen[next]
+			next++
+		}
+	}
+	d.groupIdx[n] = idx
+}
+
+// setSizes reads the Huffman parameters of a sub-table and returns the next offset.
+func (t *tbTable) setSizes(d *tbPairs, off int) int {
+	data := t.data
+	d.flags = data[off]
+	off++
+	if d.flags&tbFlagSingleValue != 0 {
+		d.minSymLen = int(data[off]) // the single stored value
+		return off + 1
+	}
+	n := 0
+	for d.groupLen[n] != 0 {
+		n++
+	}
+	tbSize := d.groupIdx[n]
+
+	d.blockSize = 1 << data[off]
+	d.span = 1 << data[off+1]
+	d.sparseIndexSize = int((tbSize + uint64(d.span) - 1) / uint64(d.span))
+	padding := int(data[off+2])
+	d.numBlocks = int(binary.LittleEndian.Uint32(data[off+3:]))
+	d.blockLengthSize = d.numBlocks + padding
+	d.maxSymLen = int(data[off+7])
+	d.minSymLen = int(data[off+8])
+	off += 9
+	d.lowestSym = off
+
+	size := d.maxSymLen - d.minSymLen + 1
+	d.base64 = make([]uint64, size)
+	for i := size - 2; i >= 0; i-- {
+		d.base64[i] = (d.base64[i+1] + uint64(t.u16(d.lowestSym+2*i)) - uint64(t.u16(d.lowestSym+2*(i+1)))) / 2
+	}
+	for i := range d.base64 {
+		d.base64[i] <<= uint(64 - i - d.minSymLen)
+	}
+	off += size * 2
+
+	d.symlen = make([]byte, t.u16(off))
+	off += 2
+	d.btree = off
+	visited := make([]bool, len(d.symlen))
+	for sym := range d.symlen {
+		if !visited[sym] {
+			d.symlen[sym] = t.setSymlen(d, sym, visited)
+		}
+	}
+	return off + len(d.symlen)*3 + len(d.symlen)&1
+}
+
+// btreeLR returns the two child symbols a symbol expands into.
+func (t *tbTable) btreeLR(d *tbPairs, sym int) (left, right int) {
+	p := t.data[d.btree+3*sym:]
+	left = int(p[1]&0xF)<<8 | int(p[0])
+	right = int(p[2])<<4 | int(p[1]>>4)
+	return left, right
+}
+
+func (t *tbTable) setSymlen(d *tbPairs, s int, visited []bool) byte {
+	visited[s] = true
+	sl, sr := t.btreeLR(d, s)
+	if sr == 0xFFF {
+		return 0
+	}
+	if !visited[sl] {
+		d.symlen[sl] = t.setSymlen(d, sl, visited)
+	}
+	if !visited[sr] {
+		d.symlen[sr] = t.setSymlen(d, sr, visited)
+	}
+	return d.symlen[sl] + d.symlen[sr] + 1
+}
+
+// setDTZMap records where the per-WDL value maps of a DTZ table start.
+func (t *tbTable) setDTZMap(off, maxFile int) int {
+	t.dtzMap = off
+	for f := 0; f <= maxFile; f++ {
+		d := t.get(0, f)
+		if d.flags&tbFlagMapped == 0 {
+			continue
+		}
+		if d.flags&tbFlagWide != 0 {
+			off += off & 1
+			for i := 0; i < 4; i++ {
+				d.mapIdx[i] = (off-t.dtzMap)/2 + 1
+				off += 2*t.u16(off) + 2
+			}
+		} else {
+			for i := 0; i < 4; i++ {
+				d.mapIdx[i] = off - t.dtzMap + 1
+				off += int(t.data[off]) + 1
+			}
+		}
+	}
+	return off + off&1
+}
+
+// decompressPairs returns the value stored at idx of a sub-table.
+func (t *tbTable) decompressPairs(d *tbPairs, idx uint64) int {
+	if d.flags&tbFlagSingleValue != 0 {
+		return d.minSymLen
+	}
+	data := t.data
+	k := idx / uint64(d.span)
+	entry := d.sparseIndex + 6*int(k)
+	block := int(binary.LittleEndian.Uint32(data[entry:]))
+	offset := int(binary.LittleEndian.Uint16(data[entry+4:]))
+	offset += int(idx%uint64(d.span)) - d.span/2
+
+	blockLen := func(b int) int { return t.u16(d.blockLength + 2*b) }
+	for offset < 0 {
+		block--
+		offset += blockLen(block) + 1
+	}
+	for offset > blockLen(block) {
+		offset -= blockLen(block) + 1
+		block++
+	}
+
+	ptr := d.blocks + block*d.blockSize
+	buf64 := binary.BigEndian.Uint64(data[ptr:])
+	ptr += 8
+	buf64Size := 64
+	var sym int
+	for {
+		l := 0
+		for buf64 < d.base64[l] {
+			l++
+		}
+		sym = int((buf64 - d.base64[l]) >> uint(64-l-d.minSymLen))
+		sym += t.u16(d.lowestSym + 2*l)
+		if offset < int(d.symlen[sym])+1 {
+			break
+		}
+		offset -= int(d.symlen[sym]) + 1
+		l += d.minSymLen
+		buf64 <<= uint(l)
+		buf64Size -= l
+		if buf64Size <= 32 {
+			buf64Size += 32
+			buf64 |= uint64(binary.BigEndian.Uint32(data[ptr:])) << uint(64-buf64Size)
+			ptr += 4
+		}
+	}
+	for d.symlen[sym] != 0 {
+		left, right := t.btreeLR(d, sym)
+		if offset < int(d.symlen[left])+1 {
+			sym = left
+		} else {
+			offset -= int(d.symlen[left]) + 1
+			sym = right
+		}
+	}
+	left, _ := t.btreeLR(d, sym)
+	return left
+}
+
+// tbPos is a position in the tablebase piece encoding (white 1-6, black 9-14).
+type tbPos struct {
+	board       [64]byte
+	blackToMove bool
+}
+
+const tbPieceLetters = " PNBRQK"
+
+func tbPosFromGame(g *engine.Game) tbPos {
+	var p tbPos
+	board := g.Board()
+	for sq := 0; sq < 64; sq++ {
+		pc := board.GetPiece(engine.Square(sq))
+		if pc.IsEmpty() {
+			continue
+		}
+		code := byte(strings.IndexByte(tbPieceLetters, pieceLetter(pc.Type)))
+		if pc.Color == engine.Black {
+			code |= 8
+		}
+		p.board[sq] = code
+	}
+	p.blackToMove = g.ActiveColor() == engine.Black
+	return p
+}
+
+// pieceLetter returns the upper case SAN letter of a piece type, 'P' for pawns.
+func pieceLetter(t engine.PieceType) byte {
+	switch t {
+	case engine.Knight:
+		return 'N'
+	case engine.Bishop:
+		return 'B'
+	case engine.Rook:
+		return 'R'
+	case engine.Queen:
+		return 'Q'
+	case engine.King:
+		return 'K'
+	}
+	return 'P'
+}
+
+// materialKey names the material like table files do, e.g. "KRPvKR".
+// With mirror set the colours are swapped.
+func (p tbPos) materialKey(mirror bool) string {
+	var side [2]strings.Builder
+	for _, letter := range "KQRBNP" {
+		code := byte(strings.IndexRune(tbPieceLetters, letter))
+		for _, c := range p.board {
+			switch c {
+			case code:
+				side[0].WriteRune(letter)
+			case code | 8:
+				side[1].WriteRune(letter)
+			}
+		}
+	}
+	if mirror {
+		return side[1].String() + "v" + side[0].String()
+	}
+	return side[0].String() + "v" + side[1].String()
+}
+
+func (p tbPos) pieceCount() int {
+	n := 0
+	for _, c := range p.board {
+		if c != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// probeTable looks up pos in a loaded table, returning the WDL score or
+// DTZ value (for DTZ tables) and a probe state.
+func (t *tbTable) probeTable(pos tbPos, wdl int) (int, int) {
+	var squares, pieces [tbMaxPieces]int
+	size := 0
+	leadPawnsCnt := 0
+	var leadPawns [64]bool
+	tbFile := 0
+
+	stmBlack := 0
+	if pos.blackToMove {
+		stmBlack = 1
+	}
+	symmetricBlackToMove := t.key == t.key2 && pos.blackToMove
+	blackStronger := pos.materialKey(false) != t.key
+	flip := symmetricBlackToMove || blackStronger
+	flipColor, flipSquares, stm := 0, 0, stmBlack
+	if flip {
+		flipColor, flipSquares, stm = 8, 56, stmBlack^1
+	}
+
+	if t.hasPawns {
+		pc := int(t.get(0, 0).pieces[0]) ^ flipColor
+		for s := 0; s < 64; s++ {
+			if int(pos.board[s]) == pc {
+				leadPawns[s] = true
+				squares[size] = s ^ flipSquares
+				size++
+			}
+		}
+		leadPawnsCnt = size
+		best := 0
+		for i := 1; i < leadPawnsCnt; i++ {
+			if tbMapPawns[squares[i]] > tbMapPawns[squares[best]] {
+				best = i
+			}
+		}
+		squares[0], squares[best] = squares[best], squares[0]
+		tbFile = squares[0] & 7
+		if tbFile > 3 {
+			tbFile = 7 - tbFile
+		}
+	}
+
+	if t.dtz {
+		flags := t.get(stm, tbFile).flags
+		if int(flags&tbFlagSTM) != stm && !(t.key == t.key2 && !t.hasPawns) {
+			return 0, tbChangeSTM
+		}
+	}
+
+	for s := 0; s < 64; s++ {
+		if pos.board[s] == 0 || leadPawns[s] {
+			continue
+		}
+		squares[size] = s ^ flipSquares
+		pieces[size] = int(pos.board[s]) ^ flipColor
+		size++
+	}
+	d := t.get(stm, tbFile)
+
+	for i := leadPawnsCnt; i < size-1; i++ {
+		for j := i + 1; j < size; j++ {
+			if int(d.pieces[i]) == pieces[j] {
+				pieces[i], pieces[j] = pieces[j], pieces[i]
+				squares[i], squares[j] = squares[j], squares[i]
+				break
+			}
+		}
+	}
+
+	if squares[0]&7 > 3 {
+		for i := 0; i < size; i++ {
+			squares[i] ^= 7
+		}
+	}
+
+	var idx uint64
+	if t.hasPawns {
+		idx = tbLeadPawnIdx[leadPawnsCnt][squares[0]]
+		rest := squares[1:leadPawnsCnt]
+		sort.SliceStable(rest, func(a, b int) bool { return tbMapPawns[rest[a]] < tbMapPawns[rest[b]] })
+		for i := 1; i < leadPawnsCnt; i++ {
+			idx += tbBinomial[i][tbMapPawns[squares[i]]]
+		}
+	} else {
+		if squares[0]>>3 > 3 {
+			for i := 0; i < size; i++ {
+				squares[i] ^= 56
+			}
+		}
+		for i := 0; i < d.groupLen[0]; i++ {
+			if offA1H8(squares[i]) == 0 {
+				continue
+			}
+			if offA1H8(squares[i]) > 0 { // mirror along the a1-h8 diagonal
+				for j := i; j < size; j++ {
+					squares[j] = ((squares[j] >> 3) | (squares[j] << 3)) & 63
+				}
+			}
+			break
+		}
+		if t.hasUnique {
+			adjust1, adjust2 := 0, 0
+			if squares[1] > squares[0] {
+				adjust1 = 1
+			}
+			if squares[2] > squares[0] {
+				adjust2++
+			}
+			if squares[2] > squares[1] {
+				adjust2++
+			}
+			switch {
+			case offA1H8(squares[0]) != 0:
+				idx = uint64((tbMapA1D1D4[squares[0]]*63+(squares[1]-adjust1))*62 + squares[2] - adjust2)
+			case offA1H8(squares[1]) != 0:
+				idx = uint64((6*63+(squares[0]>>3)*28+tbMapB1H1H7[squares[1]])*62 + squares[2] - adjust2)
+			case offA1H8(squares[2]) != 0:
+				idx = uint64(6*63*62 + 4*28*62 + (squares[0]>>3)*7*28 + ((squares[1]>>3)-adjust1)*28 + tbMapB1H1H7[squares[2]])
+			default:
+				idx = uint64(6*63*62 + 4*28*62 + 4*7*28 + (squares[0]>>3)*7*6 + ((squares[1]>>3)-adjust1)*6 + (squares[2] >> 3) - adjust2)
+			}
+		} else {
+			idx = uint64(tbMapKK[tbMapA1D1D4[squares[0]]][squares[1]])
+		}
+	}
+
+	// encode the remaining pawns, then the remaining pieces
+	idx *= d.groupIdx[0]
+	groupStart := d.groupLen[0]
+	remainingPawns := t.hasPawns && t.pawnCount[1] > 0
+	for next := 1; d.groupLen[next] != 0; next++ {
+		group := squares[groupStart : groupStart+d.groupLen[next]]
+		sort.Ints(group)
+		var n uint64
+		for i, sq := range group {
+			adjust := 0
+			for _, s := range squares[:groupStart] {
+				if sq > s {
+					adjust++
+				}
+			}
+			pawnShift := 0
+			if remainingPawns {
+				pawnShift = 8
+			}
+			n += tbBinomial[i+1][sq-adjust-pawnShift]
+		}
+		remainingPawns = false
+		idx += n * d.groupIdx[next]
+		groupStart += d.groupLen[next]
+	}
+
+	value := t.decompressPairs(d, idx)
+	if !t.dtz {
+		return value - 2, tbOK
+	}
+	return t.mapDTZ(tbFile, value, wdl), tbOK
+}
+
+// mapDTZ converts a raw DTZ table value to plies.
+func (t *tbTable) mapDTZ(f, value, wdl int) int {
+	wdlMap := [5]int{1, 3, 0, 2, 0}
+	d := t.get(0, f)
+	if d.flags&tbFlagMapped != 0 {
+		i := d.mapIdx[wdlMap[wdl+2]] + value
+		if d.flags&tbFlagWide != 0 {
+			value = t.u16(t.dtzMap + 2*i)
+		} else {
+			value = int(t.data[t.dtzMap+i])
+		}
+	}
+	if (wdl == wdlWin && d.flags&tbFlagWinPlies == 0) ||
+		(wdl == wdlLoss && d.flags&tbFlagLossPlies == 0) ||
+		wdl == wdlCursedWin || wdl == wdlBlessedLoss {
+		value *= 2
+	}
+	return value + 1
+}
+
+// tablebase gives access to the Syzygy files of one directory (or URL prefix).
+type tablebase struct {
+	dir  string
+	read func(dir, name string) ([]byte, error)
+
+	mu     sync.Mutex
+	tables map[string]*tbTable // keyed by file name, nil when missing
+}
+
+func newTablebase(dir string) *tablebase {
+	return &tablebase{dir: dir, read: readTablebaseFile, tables: map[string]*tbTable{}}
+}
+
+// table returns the WDL or DTZ table for pos, loading it on first use.
+func (tb *tablebase) table(pos tbPos, dtz bool) *tbTable {
+	ext := ".rtbw"
+	if dtz {
+		ext = ".rtbz"
+	}
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	for _, code := range []string{pos.materialKey(false), pos.materialKey(true)} {
+		name := code + ext
+		t, seen := tb.tables[name]
+		if !seen {
+			if data, err := tb.read(tb.dir, name); err == nil {
+				t, _ = newTBTable(code, data, dtz)
+			}
+			tb.tables[name] = t
+		}
+		if t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+// probe looks pos up in the WDL or DTZ table.
+func (tb *tablebase) probe(pos tbPos, dtz bool, wdl int) (int, int) {
+	if pos.pieceCount() == 2 {
+		return wdlDraw, tbOK // KvK
+	}
+	t := tb.table(pos, dtz)
+	if t == nil {
+		return 0, tbFail
+	}
+	return t.probeTable(pos, wdl)
+}
+
+// isCapture reports whether mv captures, including en passant.
+func isCapture(g *engine.Game, mv engine.Move) bool {
+	if !g.Board().GetPiece(mv.To).IsEmpty() {
+		return true
+	}
+	return mv.Piece.Type == engine.Pawn && mv.From.File() != mv.To.File()
+}
+
+// search resolves captures (and pawn moves when checkZeroing is set) before
+// trusting the table, since tables hold "don't care" values for positions
+// where such a move is best.
+func (tb *tablebase) search(g *engine.Game, checkZeroing bool) (int, int) {
+	best := wdlLoss
+	moves := g.GetAllLegalMoves()
+	moveCount := 0
+	for _, mv := range moves {
+		if !isCapture(g, mv) && (!checkZeroing || mv.Piece.Type != engine.Pawn) {
+			continue
+		}
+		moveCount++
+		if g.MakeMove(mv) != nil {
+			return wdlDraw, tbFail
+		}
+		v, state := tb.search(g, false)
+		v = -v
+		_, _ = g.UndoMove()
+		if state == tbFail {
+			return wdlDraw, tbFail
+		}
+		if v > best {
+			best = v
+			if v >= wdlWin {
+				return v, tbZeroingBestMove
+			}
+		}
+	}
+	noMoreMoves := moveCount > 0 && moveCount == len(moves)
+	value := best
+	if !noMoreMoves {
+		var state int
+		value, state = tb.probe(tbPosFromGame(g), false, wdlDraw)
+		if state == tbFail {
+			return wdlDraw, tbFail
+		}
+	}
+	if best >= value {
+		if best > wdlDraw || noMoreMoves {
+			return best, tbZeroingBestMove
+		}
+		return best, tbOK
+	}
+	return value, tbOK
+}
+
+func dtzBeforeZeroing(wdl int) int {
+	switch wdl {
+	case wdlWin:
+		return 1
+	case wdlCursedWin:
+		return 101
+	case wdlBlessedLoss:
+		return -101
+	case wdlLoss:
+		return -1
+	}
+	return 0
+}
+
+func signOf(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// probeWDL returns the WDL score of g for the side to move.
+func (tb *tablebase) probeWDL(g *engine.Game) (int, bool) {
+	v, state := tb.search(g, false)
+	return v, state != tbFail
+}
+
+// probeDTZ returns the distance to zeroing (in plies, signed like the WDL
+// score, offset by 100 for cursed wins and blessed losses).
+func (tb *tablebase) probeDTZ(g *engine.Game) (int, bool) {
+	wdl, state := tb.search(g, true)
+	if state == tbFail {
+		return 0, false
+	}
+	if wdl == wdlDraw {
+		return 0, true
+	}
+	if state == tbZeroingBestMove {
+		return dtzBeforeZeroing(wdl), true
+	}
+	dtz, state := tb.probe(tbPosFromGame(g), true, wdl)
+	if state == tbFail {
+		return 0, false
+	}
+	if state != tbChangeSTM {
+		if wdl == wdlBlessedLoss || wdl == wdlCursedWin {
+			dtz += 100
+		}
+		return dtz * signOf(wdl), true
+	}
+	// the table stores the other side to move: search one ply
+	minDTZ := 0xFFFF
+	for _, mv := range g.GetAllLegalMoves() {
+		zeroing := isCapture(g, mv) || mv.Piece.Type == engine.Pawn
+		if g.MakeMove(mv) != nil {
+			return 0, false
+		}
+		var v int
+		ok := true
+		if zeroing {
+			var w int
+			w, ok = tb.probeWDL(g)
+			v = -dtzBeforeZeroing(w)
+		} else {
+			v, ok = tb.probeDTZ(g)
+			v = -v
+		}
+		if v == 1 && len(g.GetAllLegalMoves()) == 0 && inCheck(g) {
+			minDTZ = 1 // mate
+		}
+		_, _ = g.UndoMove()
+		if !ok {
+			return 0, false
+		}
+		if !zeroing {
+			v += signOf(v)
+		}
+		if v < minDTZ && signOf(v) == signOf(wdl) {
+			minDTZ = v
+		}
+	}
+	if minDTZ == 0xFFFF {
+		return -1, true
+	}
+	return minDTZ, true
+}
@@ -0,0 +1,16 @@
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// defaultTablebaseDir is the directory named by CHESS_SYZYGY_PATH; empty disables tablebases.
+func defaultTablebaseDir() string { return os.Getenv("CHESS_SYZYGY_PATH") }
+
+// readTablebaseFile reads a table file from the local directory.
+func readTablebaseFile(dir, name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(dir, name))
+}
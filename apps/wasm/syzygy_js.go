@@ -0,0 +1,35 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"syscall/js"
+)
+
+// defaultTablebaseDir is the "syzygy" query parameter of the page URL: the
+// URL path, relative to the page, serving the table files. Empty disables
+// tablebases, so a page without tables doesn't fetch them.
+func defaultTablebaseDir() string {
+	search := js.Global().Get("location").Get("search")
+	params := js.Global().Get("URLSearchParams").New(search)
+	if dir := params.Call("get", "syzygy"); dir.Truthy() {
+		return dir.String()
+	}
+	return ""
+}
+
+// readTablebaseFile fetches a table file from the web server.
+func readTablebaseFile(dir, name string) ([]byte, error) {
+	resp, err := http.Get(dir + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP status %d for %s", resp.StatusCode, name)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,64 @@
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.rumenx.com/chess/engine"
+)
+
+// TestSyzygyProbe probes known positions against the tables in
+// CHESS_SYZYGY_PATH; it is skipped when they are not there.
+func TestSyzygyProbe(t *testing.T) {
+	dir := os.Getenv("CHESS_SYZYGY_PATH")
+	if dir == "" {
+		t.Skip("CHESS_SYZYGY_PATH not set")
+	}
+	tests := []struct {
+		name  string
+		table string // material key of the tables needed
+		fen   string
+		wdl   int
+		dtz   int // exact DTZ, or 0 to check only that its sign matches wdl
+	}{
+		{"KQvK mate in one", "KQvK", "7k/8/6K1/8/8/8/8/1Q6 w - - 0 1", wdlWin, 1},
+		{"KQvK lost for the king", "KQvK", "7k/8/6K1/8/8/8/8/1Q6 b - - 0 1", wdlLoss, 0},
+		{"KQvK queen taken", "KQvK", "8/8/8/8/8/5k2/6Q1/K7 b - - 0 1", wdlDraw, 0},
+		{"KRvK mate in one", "KRvK", "7k/8/6K1/8/8/8/8/R7 w - - 0 1", wdlWin, 1},
+		{"KRvK rook taken", "KRvK", "8/8/8/8/8/5k2/6R1/K7 b - - 0 1", wdlDraw, 0},
+		{"KPvK king on the sixth", "KPvK", "4k3/8/4K3/4P3/8/8/8/8 w - - 0 1", wdlWin, 0},
+		{"KPvK opposition", "KPvK", "4k3/8/4P3/4K3/8/8/8/8 w - - 0 1", wdlDraw, 0},
+		{"KPvK lost for the king", "KPvK", "4k3/8/4K3/4P3/8/8/8/8 b - - 0 1", wdlLoss, 0},
+	}
+	tb := newTablebase(dir)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := os.Stat(filepath.Join(dir, tt.table+".rtbw")); err != nil {
+				t.Skipf("%s.rtbw not found", tt.table)
+			}
+			g, err := engine.FromFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("FromFEN(%q): %v", tt.fen, err)
+			}
+			wdl, ok := tb.probeWDL(g)
+			if !ok || wdl != tt.wdl {
+				t.Errorf("probeWDL = %d, %v; want %d", wdl, ok, tt.wdl)
+			}
+			if _, err := os.Stat(filepath.Join(dir, tt.table+".rtbz")); err != nil {
+				return // WDL tables only
+			}
+			dtz, ok := tb.probeDTZ(g)
+			switch {
+			case !ok:
+				t.Errorf("probeDTZ failed")
+			case tt.dtz != 0 && dtz != tt.dtz:
+				t.Errorf("probeDTZ = %d, want %d", dtz, tt.dtz)
+			case signOf(dtz) != signOf(tt.wdl):
+				t.Errorf("probeDTZ = %d, want the sign of WDL %d", dtz, tt.wdl)
+			}
+		})
+	}
+}
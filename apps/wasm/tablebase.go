@@ -0,0 +1,163 @@
+package main
+
+import (
+	"log"
+
+	"go.rumenx.com/chess/ai"
+	"go.rumenx.com/chess/engine"
+)
+
+// loadTablebase returns the Syzygy tablebase in the configured location, or
+// nil when none is configured. Tables are only read when first needed.
+func loadTablebase() *tablebase {
+	dir := defaultTablebaseDir()
+	if dir == "" {
+		return nil
+	}
+	log.Printf("[DEBUG] Syzygy tablebases from %s", dir)
+	return newTablebase(dir)
+}
+
+// tablebaseGame returns a private copy of the live position when it is small
+// enough to probe, together with its halfmove clock.
+func (u *uiGame) tablebaseGame() (*engine.Game, int, bool) {
+	if u.tb == nil {
+		return nil, 0, false
+	}
+	fen := u.g.ToFEN()
+	pos, err := parseFEN(fen)
+	if err != nil || pos.castling != "-" {
+		// tables don't cover positions with castling rights
+		return nil, 0, false
+	}
+	pieces := 0
+	for _, c := range pos.board {
+		if c != 0 {
+			pieces++
+		}
+	}
+	if pieces > tbMaxPieces {
+		return nil, 0, false
+	}
+	g, err := engine.FromFEN(fen)
+	if err != nil {
+		return nil, 0, false
+	}
+	return g, pos.halfmove, true
+}
+
+// updateTablebase probes the live position once per ply for the panel line.
+func (u *uiGame) updateTablebase() {
+	if u.tbPending {
+		return
+	}
+	ply := u.ply()
+	if u.tbPly == ply {
+		return
+	}
+	u.tbPly = ply
+	u.tbText = ""
+	u.tbWDLPly = -1
+	g, _, ok := u.tablebaseGame()
+	if !ok {
+		return
+	}
+	u.tbPending = true
+	go func() {
+		text, wdl, ok := tablebaseText(u.tb, g)
+		u.tbPending = false
+		if u.tbPly == ply {
+			u.tbText = text
+			if ok {
+				u.tbWDL, u.tbWDLPly = wdl, ply
+			}
+		}
+	}()
+}
+
+// tablebaseText describes the result for the side to move and returns the
+// WDL score as well. N counts moves to the next capture or pawn move (DTZ),
+// which is what Syzygy tables store.
+func tablebaseText(tb *tablebase, g *engine.Game) (string, int, bool) {
+	side := tr(g.ActiveColor().String())
+	wdl, ok := tb.probeWDL(g)
+	if !ok {
+		return "", 0, false
+	}
+	switch wdl {
+	case wdlCursedWin, wdlBlessedLoss:
+		return tr("TB: Draw (50-move rule)"), wdl, true
+	case wdlDraw:
+		return tr("TB: Draw"), wdl, true
+	}
+	dtz, ok := tb.probeDTZ(g)
+	n := (absInt(dtz) + 1) / 2
+	switch {
+	case wdl > 0 && ok:
+		return trf("TB: Win in %d for %s", n, side), wdl, true
+	case wdl > 0:
+		return trf("TB: Win for %s", side), wdl, true
+	case ok:
+		return trf("TB: Loss in %d for %s", n, side), wdl, true
+	}
+	return trf("TB: Loss for %s", side), wdl, true
+}
+
+// tablebaseMove returns the tablebase-perfect move for the AI at the higher
+// difficulties and in endgame drills: the fastest safe win, else a draw, else
+// the longest defence.
+func (u *uiGame) tablebaseMove() (engine.Move, bool) {
+	if u.endgame == nil && u.difficulty != ai.DifficultyHard && u.difficulty != ai.DifficultyExpert {
+		return engine.Move{}, false
+	}
+	g, halfmove, ok := u.tablebaseGame()
+	if !ok {
+		return engine.Move{}, false
+	}
+	var best engine.Move
+	bestRank, found := 0, false
+	for _, mv := range g.GetAllLegalMoves() {
+		zeroing := isCapture(g, mv) || mv.Piece.Type == engine.Pawn
+		if g.MakeMove(mv) != nil {
+			return engine.Move{}, false
+		}
+		var dtz int
+		if zeroing {
+			wdl, ok := u.tb.probeWDL(g)
+			if !ok {
+				_, _ = g.UndoMove()
+				return engine.Move{}, false
+			}
+			dtz = dtzBeforeZeroing(-wdl)
+		} else {
+			d, ok := u.tb.probeDTZ(g)
+			if !ok {
+				_, _ = g.UndoMove()
+				return engine.Move{}, false
+			}
+			dtz = -d + signOf(-d)
+		}
+		if len(g.GetAllLegalMoves()) == 0 && inCheck(g) {
+			dtz = 1 // mate
+		}
+		_, _ = g.UndoMove()
+
+		clock := halfmove
+		if zeroing {
+			clock = 0
+		}
+		var rank int
+		switch {
+		case dtz > 0 && dtz+clock <= 100:
+			rank = 100000 - dtz
+		case dtz > 0: // won on the board, but the 50-move rule interferes
+			rank = 50000 - dtz
+		case dtz < 0:
+			rank = -100000 - dtz // resist as long as possible
+		}
+		if !found || rank > bestRank {
+			best, bestRank, found = mv, rank, true
+		}
+	}
+	return best, found
+}
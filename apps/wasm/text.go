@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"image/color"
+	"log"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// uiFontTTF is DejaVu Sans (see fonts/LICENSE); it covers Latin, Greek and
+// Cyrillic as well as the chess figurines.
+//
+//go:embed fonts/DejaVuSans.ttf
+var uiFontTTF []byte
+
+// Text sizes follow the board so the panel keeps its proportions when
+// boardPixels changes.
+const (
+	textSize      = squareSize * 3 / 20.0 // panel text
+	smallTextSize = squareSize / 8.0      // eval bar and graph labels
+	titleTextSize = squareSize / 4.0      // report heading
+)
+
+// lineHeight is the distance between two lines of panel text.
+var lineHeight = int(math.Ceil(textSize * 1.2))
+
+// panelTextWidth is the width available to a line of panel text.
+const panelTextWidth = panelWidth - 16
+
+var (
+	uiFontSource = loadUIFont()
+	panelFace    = &text.GoTextFace{Source: uiFontSource, Size: textSize}
+	smallFace    = &text.GoTextFace{Source: uiFontSource, Size: smallTextSize}
+	titleFace    = &text.GoTextFace{Source: uiFontSource, Size: titleTextSize}
+)
+
+func loadUIFont() *text.GoTextFaceSource {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(uiFontTTF))
+	if err != nil {
+		log.Fatalf("[ERROR] Loading UI font: %v", err)
+	}
+	return src
+}
+
+// printText draws one line of panel text in white with its top-left corner at (x, y).
+func printText(screen *ebiten.Image, s string, x, y int) {
+	drawText(screen, s, panelFace, x, y, color.White)
+}
+
+// drawText draws one line of s in face and colour c with its top-left corner at (x, y).
+func drawText(screen *ebiten.Image, s string, face text.Face, x, y int, c color.Color) {
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(float64(x), float64(y))
+	op.ColorScale.ScaleWithColor(c)
+	text.Draw(screen, s, face, op)
+}
+
+// textWidth measures s in face, rounded up to whole pixels.
+func textWidth(s string, face text.Face) int {
+	return int(math.Ceil(text.Advance(s, face)))
+}
+
+// wrapText splits s at spaces into lines no wider than width pixels of
+// panel text; continuation lines are indented.
+func wrapText(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		switch {
+		case line == "":
+			line = word
+		case textWidth(line+" "+word, panelFace) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = "  " + word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"go.rumenx.com/chess/engine"
+)
+
+const (
+	longPressTicks = 30 // half a second held still starts an annotation
+	dragSlop       = 10 // pixels a finger may wander before a tap becomes a drag
+	panelTouchSlop = 10 // extra pixels around panel controls that still hit them
+	// touchMouseQuiet is how many ticks the mouse is ignored after a touch,
+	// so a browser's emulated mouse events do not click a second time.
+	touchMouseQuiet = 30
+)
+
+// touchGesture follows the one finger that is read at a time. On the board
+// a tap clicks a square (tap-tap moves), a drag carries the piece to its
+// target and a long press draws a circle, or an arrow when the finger then
+// moves. In the panel a tap presses the nearest control and a drag scrolls.
+type touchGesture struct {
+	id        ebiten.TouchID
+	x0, y0    int // where the finger went down
+	x, y      int // where it is now, or was last seen
+	from      engine.Square
+	onBoard   bool
+	dragging  bool // moved further than dragSlop
+	holding   bool // carrying a piece
+	marking   bool // long press: drawing an annotation
+	scrolled  int  // panel scroll applied so far, in pixels
+	cancelled bool // a second finger came down, as in a pinch
+}
+
+// handleTouch reads the touch screen with Ebiten's touch IDs.
+func (u *uiGame) handleTouch() {
+	ids := ebiten.AppendTouchIDs(nil)
+	if len(ids) > 0 {
+		u.touchQuiet = touchMouseQuiet
+	} else if u.touchQuiet > 0 {
+		u.touchQuiet--
+	}
+	if u.touch == nil {
+		for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
+			if len(ids) == 1 {
+				x, y := ebiten.TouchPosition(id)
+				sq, onBoard := u.squareAt(x, y)
+				u.touch = &touchGesture{id: id, x0: x, y0: y, x: x, y: y, from: sq, onBoard: onBoard}
+			}
+		}
+		return
+	}
+	t := u.touch
+	if len(ids) > 1 && !t.cancelled {
+		// two fingers are a pinch or a slip, never a move
+		t.cancelled = true
+		u.markFrom = nil
+	}
+	if inpututil.IsTouchJustReleased(t.id) {
+		u.touch = nil
+		if !t.cancelled {
+			u.endTouch(t)
+		}
+		return
+	}
+	if !slices.Contains(ids, t.id) {
+		u.touch = nil // lost without a release, e.g. when the page was hidden
+		return
+	}
+	t.x, t.y = ebiten.TouchPosition(t.id)
+	if t.cancelled {
+		return
+	}
+	if !t.dragging && (absInt(t.x-t.x0) > dragSlop || absInt(t.y-t.y0) > dragSlop) {
+		t.dragging = true
+		if t.onBoard && !t.marking {
+			t.holding = u.pickUp(t.from)
+		}
+	}
+	switch {
+	case t.onBoard && !t.dragging && !t.marking && inpututil.TouchPressDuration(t.id) >= longPressTicks:
+		if u.cancelPremoves() {
+			// like a right-click, a long press first cancels premoves
+			t.cancelled = true
+			return
+		}
+		t.marking = true
+		from := t.from
+		u.markFrom = &from
+	case !t.onBoard && t.dragging:
+		if notches := (t.y - t.y0 - t.scrolled) / lineHeight; notches != 0 {
+			if w := u.panel.near(t.x0, t.y0, panelTouchSlop); w != nil {
+				w.scroll(float64(notches))
+			}
+			t.scrolled += notches * lineHeight
+		}
+	}
+}
+
+// endTouch finishes a gesture when its finger lifts.
+func (u *uiGame) endTouch(t *touchGesture) {
+	to, onBoard := u.squareAt(t.x, t.y)
+	switch {
+	case t.marking:
+		u.markFrom = nil
+		if onBoard {
+			u.toggleMark(boardMark{t.from, to, heldMarkColor()})
+		}
+	case t.holding:
+		if onBoard && to != t.from {
+			u.clickSquare(to)
+		}
+	case !t.dragging && t.onBoard:
+		u.clickSquare(t.from)
+	case !t.dragging:
+		u.panel.tap(t.x0, t.y0, panelTouchSlop)
+	}
+}
+
+// pickUp selects the piece on sq for a drag, as a first tap would, and
+// reports whether it is now held.
+func (u *uiGame) pickUp(sq engine.Square) bool {
+	if !u.viewing() && u.aiToMove() {
+		if u.premoveFrom == nil || *u.premoveFrom != sq {
+			u.premoveFrom = nil
+			u.clickPremove(sq)
+		}
+		return u.premoveFrom != nil && *u.premoveFrom == sq
+	}
+	if u.selected == nil || *u.selected != sq {
+		u.selected = nil
+		u.legalTargets = map[engine.Square]bool{}
+		u.clickSquare(sq)
+	}
+	return u.selected != nil && *u.selected == sq
+}
+
+// pointer returns the position of the finger being read, or else of the mouse.
+func (u *uiGame) pointer() (int, int) {
+	if u.touch != nil {
+		return u.touch.x, u.touch.y
+	}
+	return ebiten.CursorPosition()
+}
+
+// drawTouchDrag draws the piece carried by a finger above the fingertip, so
+// it stays in sight.
+func (u *uiGame) drawTouchDrag(screen *ebiten.Image) {
+	t := u.touch
+	if t == nil || !t.holding || t.cancelled {
+		return
+	}
+	p := u.premoveBoard()[t.from] // the live board when no premoves are queued
+	if p.IsEmpty() {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(t.x-squareSize/2), float64(t.y-squareSize))
+	op.ColorScale.ScaleAlpha(0.85)
+	screen.DrawImage(u.pieceImage(p), op)
+}
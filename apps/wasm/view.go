@@ -0,0 +1,61 @@
+package main
+
+import "go.rumenx.com/chess/engine"
+
+// setViewPly shows the position after the given number of half-moves
+// without touching the live game. Passing the live ply returns to play.
+func (u *uiGame) setViewPly(ply int) {
+	history := u.history()
+	if ply < 0 || ply >= len(history) {
+		u.leaveView()
+		return
+	}
+	g, err := replayMoves(u.startFEN, history[:ply])
+	if err != nil {
+		u.leaveView()
+		return
+	}
+	u.viewPly = ply
+	u.viewGame = g
+	u.selected = nil
+	u.legalTargets = map[engine.Square]bool{}
+	u.flashMsg(trf("Viewing ply %d, click board to return", ply))
+}
+
+// leaveView returns the board to the live position.
+func (u *uiGame) leaveView() {
+	u.viewPly = -1
+	u.viewGame = nil
+}
+
+// viewing reports whether a past position is displayed.
+func (u *uiGame) viewing() bool { return u.viewGame != nil }
+
+// displayGame returns the game whose position is drawn on the board.
+func (u *uiGame) displayGame() *engine.Game {
+	if u.viewGame != nil {
+		return u.viewGame
+	}
+	return u.g
+}
+
+// displayPly returns the ply of the displayed position.
+func (u *uiGame) displayPly() int {
+	if u.viewGame != nil {
+		return u.viewPly
+	}
+	return u.ply()
+}
+
+// displayLastMove returns the move that led to the displayed position.
+func (u *uiGame) displayLastMove() *engine.Move {
+	if u.viewGame == nil {
+		return u.lastMove
+	}
+	history := u.history()
+	if u.viewPly == 0 || u.viewPly > len(history) {
+		return nil
+	}
+	mv := history[u.viewPly-1]
+	return &mv
+}
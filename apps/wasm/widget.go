@@ -0,0 +1,316 @@
+package main
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// tooltipDelay is how many ticks the pointer has to rest on a widget before
+// its tooltip appears.
+const tooltipDelay = 30
+
+// widget is an element of the side panel. Widgets are built once (see
+// panel.go) and each draws and hit-tests itself, so a click always lands on
+// what is drawn. Rectangles are in screen coordinates.
+type widget interface {
+	bounds() image.Rectangle
+	draw(screen *ebiten.Image, hover bool)
+	click(x, y int)
+	scroll(dy float64)
+	tip() string
+	// controls are the targets offered to keyboard focus and screen readers.
+	controls() []panelControl
+}
+
+// panelControl is something in the panel that can be activated from the
+// keyboard; the label names it for screen readers and on, when set, tells
+// whether it is pressed or selected.
+type panelControl struct {
+	label    func() string
+	on       func() bool
+	rect     image.Rectangle
+	activate func()
+}
+
+// widgetBase holds a widget's rectangle and tooltip and gives the no-op
+// defaults.
+type widgetBase struct {
+	rect    image.Rectangle
+	tooltip string // English, translated when shown
+}
+
+func (w *widgetBase) bounds() image.Rectangle  { return w.rect }
+func (w *widgetBase) click(x, y int)           {}
+func (w *widgetBase) scroll(dy float64)        {}
+func (w *widgetBase) controls() []panelControl { return nil }
+
+func (w *widgetBase) tip() string {
+	if w.tooltip == "" {
+		return ""
+	}
+	return tr(w.tooltip)
+}
+
+// button runs action when clicked. lit, when set, keeps it highlighted, for
+// example while the hint it asked for is searched.
+type button struct {
+	widgetBase
+	name   string // accessible name, English
+	label  func() string
+	lit    func() bool
+	action func()
+}
+
+func (b *button) draw(screen *ebiten.Image, hover bool) {
+	r := b.rect
+	drawSelectableBox(screen, r.Min.X, r.Min.Y, r.Dx(), r.Dy(), b.label(), hover || (b.lit != nil && b.lit()))
+}
+
+func (b *button) click(x, y int) { b.action() }
+
+func (b *button) controls() []panelControl {
+	return []panelControl{{func() string { return tr(b.name) }, nil, b.rect, b.action}}
+}
+
+// toggle is a button with a state, shown by the highlight and a lamp at its
+// right edge.
+type toggle struct {
+	button
+	on func() bool
+}
+
+func (t *toggle) draw(screen *ebiten.Image, hover bool) {
+	r := t.rect
+	on := t.on()
+	drawSelectableBox(screen, r.Min.X, r.Min.Y, r.Dx(), r.Dy(), t.label(), hover || on)
+	lamp := color.RGBA{0x55, 0x55, 0x55, 0xFF}
+	if on {
+		lamp = color.RGBA{0x7C, 0xE0, 0x7C, 0xFF}
+	}
+	vector.DrawFilledRect(screen, float32(r.Max.X-9), float32(r.Min.Y+r.Dy()/2-3), 5, 6, lamp, false)
+}
+
+func (t *toggle) controls() []panelControl {
+	cs := t.button.controls()
+	cs[0].on = t.on
+	return cs
+}
+
+// list is a column of choices of which one is selected.
+type list struct {
+	widgetBase
+	items    func() []string
+	selected func() int
+	name     func(i int) string // accessible name of item i, translated
+	choose   func(i int)
+	rowH     int // row pitch; rows are drawn 2 pixels shorter
+}
+
+func (l *list) row(i int) image.Rectangle {
+	y := l.rect.Min.Y + i*l.rowH
+	return image.Rect(l.rect.Min.X, y, l.rect.Max.X, y+l.rowH-2)
+}
+
+func (l *list) draw(screen *ebiten.Image, hover bool) {
+	x, y := ebiten.CursorPosition()
+	sel := l.selected()
+	for i, item := range l.items() {
+		r := l.row(i)
+		over := hover && image.Pt(x, y).In(r)
+		drawSelectableBox(screen, r.Min.X, r.Min.Y, r.Dx(), r.Dy(), item, i == sel || over)
+	}
+}
+
+func (l *list) click(x, y int) {
+	if i := (y - l.rect.Min.Y) / l.rowH; i >= 0 && i < len(l.items()) && image.Pt(x, y).In(l.row(i)) {
+		l.choose(i)
+	}
+}
+
+func (l *list) controls() []panelControl {
+	var cs []panelControl
+	for i := range l.items() {
+		i := i
+		cs = append(cs, panelControl{func() string { return l.name(i) }, func() bool { return l.selected() == i }, l.row(i), func() { l.choose(i) }})
+	}
+	return cs
+}
+
+// textBox shows lines of panel text clipped to its rectangle; the mouse
+// wheel scrolls lines that do not fit. A box that follows its end keeps the
+// last lines in view until scrolled back.
+type textBox struct {
+	widgetBase
+	lines  func() []string
+	follow bool
+	offset int // lines scrolled from the start, or back from the end when following
+}
+
+// first returns the index of the first visible line of n and clamps offset.
+func (t *textBox) first(n int) int {
+	extra := n - t.rect.Dy()/lineHeight
+	if extra < 0 {
+		extra = 0
+	}
+	t.offset = min(max(t.offset, 0), extra)
+	if t.follow {
+		return extra - t.offset
+	}
+	return t.offset
+}
+
+func (t *textBox) draw(screen *ebiten.Image, hover bool) {
+	lines := t.lines()
+	if t.rect.Empty() || len(lines) == 0 {
+		return
+	}
+	clip := screen.SubImage(t.rect).(*ebiten.Image)
+	start := t.first(len(lines))
+	for i := start; i < len(lines); i++ {
+		y := t.rect.Min.Y + (i-start)*lineHeight
+		if y >= t.rect.Max.Y {
+			break
+		}
+		printText(clip, lines[i], t.rect.Min.X, y)
+	}
+	if visible := t.rect.Dy() / lineHeight; len(lines) > visible {
+		// scroll thumb at the right edge
+		h := float32(t.rect.Dy()) * float32(visible) / float32(len(lines))
+		y := float32(t.rect.Min.Y) + float32(t.rect.Dy())*float32(start)/float32(len(lines))
+		vector.DrawFilledRect(screen, float32(t.rect.Max.X-3), y, 3, h, color.RGBA{0x88, 0x88, 0x88, 0xFF}, false)
+	}
+}
+
+func (t *textBox) scroll(dy float64) {
+	notches := int(dy)
+	if notches == 0 {
+		return
+	}
+	if t.follow {
+		t.offset += notches
+	} else {
+		t.offset -= notches
+	}
+}
+
+// panel holds the widgets of the side panel and routes the pointer to them.
+type panel struct {
+	widgets []widget
+	layout  func() // places the widgets whose size follows their content
+	hover   widget
+	rested  int // ticks the pointer has stayed on hover
+}
+
+// at returns the topmost widget containing (x, y), or nil.
+func (p *panel) at(x, y int) widget {
+	for i := len(p.widgets) - 1; i >= 0; i-- {
+		if image.Pt(x, y).In(p.widgets[i].bounds()) {
+			return p.widgets[i]
+		}
+	}
+	return nil
+}
+
+// update lays the panel out and tracks the pointer for hover highlights,
+// tooltips and the mouse wheel.
+func (p *panel) update(x, y int) {
+	if p.layout != nil {
+		p.layout()
+	}
+	w := p.at(x, y)
+	if w != p.hover {
+		p.hover, p.rested = w, 0
+	} else {
+		p.rested++
+	}
+	if _, dy := ebiten.Wheel(); dy != 0 && w != nil {
+		w.scroll(dy)
+	}
+}
+
+// click sends a left click to the widget under it; it reports whether one took it.
+func (p *panel) click(x, y int) bool {
+	w := p.at(x, y)
+	if w == nil {
+		return false
+	}
+	w.click(x, y)
+	return true
+}
+
+// near returns the widget at (x, y) or else the closest one within slop
+// pixels, so a finger need not land exactly on a control.
+func (p *panel) near(x, y, slop int) widget {
+	if w := p.at(x, y); w != nil {
+		return w
+	}
+	var best widget
+	bestD := slop + 1
+	for _, w := range p.widgets {
+		r := w.bounds()
+		if r.Empty() {
+			continue
+		}
+		dx := max(r.Min.X-x, 0, x-(r.Max.X-1))
+		dy := max(r.Min.Y-y, 0, y-(r.Max.Y-1))
+		if d := max(dx, dy); d < bestD {
+			best, bestD = w, d
+		}
+	}
+	return best
+}
+
+// tap clicks the widget near (x, y) as if the point were inside it.
+func (p *panel) tap(x, y, slop int) {
+	w := p.near(x, y, slop)
+	if w == nil {
+		return
+	}
+	r := w.bounds()
+	w.click(min(max(x, r.Min.X), r.Max.X-1), min(max(y, r.Min.Y), r.Max.Y-1))
+}
+
+// controls lists the keyboard targets of all widgets in Tab order.
+func (p *panel) controls() []panelControl {
+	var cs []panelControl
+	for _, w := range p.widgets {
+		cs = append(cs, w.controls()...)
+	}
+	return cs
+}
+
+func (p *panel) draw(screen *ebiten.Image) {
+	for _, w := range p.widgets {
+		w.draw(screen, w == p.hover)
+	}
+	if p.hover != nil && p.rested >= tooltipDelay {
+		if tip := p.hover.tip(); tip != "" {
+			x, y := ebiten.CursorPosition()
+			drawTooltip(screen, tip, x, y)
+		}
+	}
+}
+
+// drawTooltip shows tip in a box below and left of the pointer, kept on screen.
+func drawTooltip(screen *ebiten.Image, tip string, x, y int) {
+	lines := wrapText(tip, panelTextWidth-8)
+	w := 0
+	for _, l := range lines {
+		w = max(w, textWidth(l, panelFace))
+	}
+	w += 12
+	h := len(lines)*lineHeight + 8
+	bx := min(max(x-w+16, 0), windowW-w)
+	by := y + 20
+	if by+h > windowH {
+		by = y - h - 4
+	}
+	vector.DrawFilledRect(screen, float32(bx), float32(by), float32(w), float32(h), color.RGBA{0x10, 0x10, 0x10, 0xF0}, false)
+	vector.StrokeRect(screen, float32(bx), float32(by), float32(w), float32(h), 1, color.RGBA{0x90, 0x90, 0x90, 0xFF}, false)
+	for i, l := range lines {
+		printText(screen, l, bx+6, by+4+i*lineHeight)
+	}
+}